@@ -0,0 +1,163 @@
+package flags
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/mevansam/goforms/forms"
+)
+
+// Binds the fields of an input form to
+// command line flags. Values of flags
+// provided on the command line are set
+// on the form's fields.
+type FlagBinder struct {
+	inputGroup *forms.InputGroup
+	flagSet    *pflag.FlagSet
+	tags       []string
+
+	// names of fields bound to flags in form order
+	fieldNames []string
+	flagNames  map[string]string
+	flagValues map[string]*string
+}
+
+func NewFlagBinder(
+	input forms.Input,
+	flagSet *pflag.FlagSet,
+	tags ...string,
+) (*FlagBinder, error) {
+
+	var (
+		ok         bool
+		inputGroup *forms.InputGroup
+	)
+
+	if inputGroup, ok = input.(*forms.InputGroup); !ok {
+		return nil, fmt.Errorf("input is not of type forms.InputGroup: %#v", input)
+	}
+
+	fb := &FlagBinder{
+		inputGroup: inputGroup,
+		flagSet:    flagSet,
+		tags:       tags,
+
+		fieldNames: []string{},
+		flagNames:  make(map[string]string),
+		flagValues: make(map[string]*string),
+	}
+
+	for _, f := range inputGroup.InputFields() {
+		if !f.Enabled(false, tags...) {
+			continue
+		}
+
+		name := FlagName(f.Name())
+		if flagSet.Lookup(name) != nil {
+			return nil, fmt.Errorf(
+				"a flag with name '%s' for field '%s' has already been defined",
+				name, f.Name())
+		}
+
+		defaultValue := ""
+		if value := f.DefaultValue(); value != nil && !f.Sensitive() {
+			defaultValue = *value
+		}
+		usage := f.LongDescription()
+		if valueFromFile, _ := f.ValueFromFile(); valueFromFile {
+			usage = "path to a file with the " + usage
+		}
+
+		fb.fieldNames = append(fb.fieldNames, f.Name())
+		fb.flagNames[f.Name()] = name
+		fb.flagValues[f.Name()] = flagSet.String(name, defaultValue, usage)
+	}
+	return fb, nil
+}
+
+// in: fieldName - the name of a form field
+// out: the name of the flag for the field
+func FlagName(fieldName string) string {
+	return strings.ReplaceAll(fieldName, "_", "-")
+}
+
+// sets the values of the flags provided on the
+// command line on the corresponding form fields
+//
+// out: an error if a flag's value is invalid or a flag
+//      was provided for a field which is not enabled
+//      by the values of the fields it depends on
+func (fb *FlagBinder) ApplyFlags() error {
+
+	var (
+		err   error
+		field *forms.InputField
+	)
+
+	if err = fb.validateExclusivity(fb.inputGroup, make(map[string]bool)); err != nil {
+		return err
+	}
+
+	// fields are set in form order so that
+	// values of fields that others depend
+	// on are set first
+	for _, name := range fb.fieldNames {
+		if !fb.flagSet.Changed(fb.flagNames[name]) {
+			continue
+		}
+		if field, err = fb.inputGroup.GetInputField(name); err != nil {
+			return err
+		}
+		if !field.Enabled(true, fb.tags...) {
+			return fmt.Errorf(
+				"flag '--%s' cannot be provided as field '%s' is not enabled by the values of the fields it depends on",
+				fb.flagNames[name], name)
+		}
+		if err = fb.inputGroup.SetFieldValue(name, *fb.flagValues[name]); err != nil {
+			return fmt.Errorf(
+				"invalid value for flag '--%s': %s",
+				fb.flagNames[name], err.Error())
+		}
+		field.SetInput()
+	}
+	return nil
+}
+
+// in: input   - input whose containers should be validated
+// in: visited - set of containers that have been validated
+// out: an error if more than one flag has been provided
+//      for inputs of the same container
+func (fb *FlagBinder) validateExclusivity(input forms.Input, visited map[string]bool) error {
+
+	for _, i := range input.Inputs() {
+
+		if i.Type() == forms.Repeatable {
+			// fields of repeatable groups
+			// are not bound to flags
+			continue
+		}
+		if i.Type() == forms.Container && !visited[i.Name()] {
+			visited[i.Name()] = true
+
+			provided := []string{}
+			for _, ii := range i.Inputs() {
+				if name, exists := fb.flagNames[ii.Name()]; exists && fb.flagSet.Changed(name) {
+					provided = append(provided, "--"+name)
+				}
+			}
+			if len(provided) > 1 {
+				sort.Strings(provided)
+				return fmt.Errorf(
+					"only one of the flags %s may be provided",
+					strings.Join(provided, ", "))
+			}
+		}
+		if err := fb.validateExclusivity(i, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
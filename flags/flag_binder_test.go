@@ -0,0 +1,113 @@
+package flags_test
+
+import (
+	"github.com/spf13/pflag"
+
+	"github.com/mevansam/goforms/flags"
+	"github.com/mevansam/goforms/forms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	test_data "github.com/mevansam/goforms/test/data"
+)
+
+var _ = Describe("Flag Binder", func() {
+
+	var (
+		err error
+
+		inputGroup *forms.InputGroup
+		flagSet    *pflag.FlagSet
+	)
+
+	BeforeEach(func() {
+
+		inputGroup = test_data.NewTestInputCollection().Group("input-form")
+		for _, f := range inputGroup.InputFields() {
+			err = f.SetValueRef(new(string))
+			Expect(err).ToNot(HaveOccurred())
+		}
+		flagSet = pflag.NewFlagSet("test", pflag.ContinueOnError)
+	})
+
+	It("registers flags for enabled fields", func() {
+
+		_, err = flags.NewFlagBinder(inputGroup, flagSet, "tag2")
+		Expect(err).NotTo(HaveOccurred())
+
+		names := []string{}
+		flagSet.VisitAll(func(f *pflag.Flag) {
+			names = append(names, f.Name)
+		})
+		Expect(names).To(Equal([]string{"attrib13"}))
+
+		flagSet = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		_, err = flags.NewFlagBinder(inputGroup, flagSet)
+		Expect(err).NotTo(HaveOccurred())
+
+		flag := flagSet.Lookup("attrib14")
+		Expect(flag).NotTo(BeNil())
+		Expect(flag.DefValue).To(Equal("default value for attrib14"))
+		Expect(flag.Usage).To(Equal("description for attrib14."))
+
+		flag = flagSet.Lookup("attrib12")
+		Expect(flag).NotTo(BeNil())
+		Expect(flag.Usage).To(Equal("description for attrib12. It will be sourced from the environment variable ATTRIB12_ENV1 if not provided."))
+	})
+
+	It("sets field values from parsed flags", func() {
+
+		fb, err := flags.NewFlagBinder(inputGroup, flagSet)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = flagSet.Parse([]string{
+			"--attrib12", "value for attrib12 - A",
+			"--attrib121", "value for attrib121",
+			"--attrib14", "value for attrib14",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		err = fb.ApplyFlags()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inputGroup.InputValues()).To(Equal(map[string]string{
+			"attrib12":  "value for attrib12 - A",
+			"attrib121": "value for attrib121",
+			"attrib14":  "value for attrib14",
+		}))
+	})
+
+	It("rejects flags for fields which are not enabled", func() {
+
+		fb, err := flags.NewFlagBinder(inputGroup, flagSet)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = flagSet.Parse([]string{
+			"--attrib12", "value for attrib12 - A",
+			"--attrib122", "value for attrib122",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		err = fb.ApplyFlags()
+		Expect(err).To(MatchError("flag '--attrib122' cannot be provided as field 'attrib122' is not enabled by the values of the fields it depends on"))
+
+		value, err := inputGroup.GetFieldValue("attrib122")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(BeNil())
+	})
+
+	It("rejects flags for more than one field of a container", func() {
+
+		fb, err := flags.NewFlagBinder(inputGroup, flagSet)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = flagSet.Parse([]string{
+			"--attrib12", "value for attrib12",
+			"--attrib121", "value for attrib121",
+			"--attrib122", "value for attrib122",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		err = fb.ApplyFlags()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("only one of the flags --attrib121, --attrib122 may be provided"))
+	})
+})
@@ -0,0 +1,125 @@
+package forms
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Converts between the string value of a field
+// and the typed value of the object bound to it
+type valueCodec struct {
+	// parses the string value into a new
+	// value of the bound object's type
+	parse func(value string) (reflect.Value, error)
+	// formats the bound object's value
+	format func(value reflect.Value) string
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	stringSliceType     = reflect.TypeOf([]string{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// in: t - the type of the object being bound
+// out: the codec for the type. false if the type is not supported
+func valueCodecFor(t reflect.Type) (*valueCodec, bool) {
+
+	switch {
+	case t == durationType:
+		return &valueCodec{
+			parse: func(value string) (reflect.Value, error) {
+				d, err := time.ParseDuration(value)
+				return reflect.ValueOf(d), err
+			},
+			format: func(value reflect.Value) string {
+				return value.Interface().(time.Duration).String()
+			},
+		}, true
+
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return &valueCodec{
+			parse: func(value string) (reflect.Value, error) {
+				v := reflect.New(t)
+				err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+				return v.Elem(), err
+			},
+			format: func(value reflect.Value) string {
+				if t.Implements(textMarshalerType) {
+					if text, err := value.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+						return string(text)
+					}
+				} else if reflect.PointerTo(t).Implements(textMarshalerType) {
+					v := reflect.New(t)
+					v.Elem().Set(value)
+					if text, err := v.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+						return string(text)
+					}
+				}
+				return fmt.Sprintf("%v", value.Interface())
+			},
+		}, true
+
+	case t == stringSliceType:
+		return &valueCodec{
+			parse: func(value string) (reflect.Value, error) {
+				items, err := parseListValue(value)
+				return reflect.ValueOf(items), err
+			},
+			format: func(value reflect.Value) string {
+				return formatListValue(value.Interface().([]string))
+			},
+		}, true
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &valueCodec{
+			parse: func(value string) (reflect.Value, error) {
+				b, err := strconv.ParseBool(value)
+				return reflect.ValueOf(b).Convert(t), err
+			},
+			format: func(value reflect.Value) string {
+				return strconv.FormatBool(value.Bool())
+			},
+		}, true
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &valueCodec{
+			parse: func(value string) (reflect.Value, error) {
+				i, err := strconv.ParseInt(value, 10, t.Bits())
+				return reflect.ValueOf(i).Convert(t), err
+			},
+			format: func(value reflect.Value) string {
+				return strconv.FormatInt(value.Int(), 10)
+			},
+		}, true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &valueCodec{
+			parse: func(value string) (reflect.Value, error) {
+				u, err := strconv.ParseUint(value, 10, t.Bits())
+				return reflect.ValueOf(u).Convert(t), err
+			},
+			format: func(value reflect.Value) string {
+				return strconv.FormatUint(value.Uint(), 10)
+			},
+		}, true
+
+	case reflect.Float32, reflect.Float64:
+		return &valueCodec{
+			parse: func(value string) (reflect.Value, error) {
+				f, err := strconv.ParseFloat(value, t.Bits())
+				return reflect.ValueOf(f).Convert(t), err
+			},
+			format: func(value reflect.Value) string {
+				return strconv.FormatFloat(value.Float(), 'g', -1, t.Bits())
+			},
+		}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,142 @@
+package forms
+
+import (
+	"sync"
+)
+
+// A form which can be copied. Implementations of
+// InputForm are not required to support copying
+// so it is asserted when needed.
+type CloneableForm interface {
+	InputForm
+
+	Clone(target interface{}) (*InputGroup, error)
+}
+
+// Creates a copy of the group with its containers,
+// fields, dependencies, filters, hints, validators
+// and repeatable groups. listeners subscribed to
+// changes of the group's fields are not copied.
+//
+// in: target - optional pointer to a struct the fields of the
+//              copy are bound to via their "form_field" tags.
+//              if nil the fields of the copy are bound to
+//              copies of the values bound to the group.
+// out: a copy of the group that does not share any
+//      state with the group other than its secret store.
+//      if bound to a target only the fields with values
+//      bound from the target have their input set.
+func (g *InputGroup) Clone(target interface{}) (*InputGroup, error) {
+
+	var (
+		err error
+	)
+
+	clone := &InputGroup{
+		name:        g.name,
+		description: g.description,
+		displayName: g.displayName,
+		inputs:      []Input{},
+
+		containers:   make(map[int]*InputGroup),
+		fieldNameSet: make(map[string]Input),
+
+		fieldValueLookupHints: make(map[string][]string),
+
+		localization: g.localization.clone(),
+		secrets:      &secrets{},
+		igMx:         &sync.RWMutex{},
+	}
+
+	g.igMx.RLock()
+	defer g.igMx.RUnlock()
+
+	clone.secrets.store = g.secrets.store
+	if err = g.copyDefinition(clone); err != nil {
+		return nil, err
+	}
+	if target != nil {
+		if err = clone.BindFields(target); err != nil {
+			return nil, err
+		}
+		clone.setBoundInputs()
+	} else if err = g.copyValues(clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// in: target - a copy of this group's definition whose
+//              fields are bound to copies of the values
+//              bound to this group
+func (g *InputGroup) copyValues(target *InputGroup) error {
+
+	var (
+		err error
+
+		value *string
+		field *InputField
+
+		rg       *RepeatableGroup
+		itemCopy *InputGroup
+	)
+
+	for name, i := range g.fieldNameSet {
+		switch input := i.(type) {
+
+		case *InputField:
+			if field, err = target.getInputField(name); err != nil {
+				return err
+			}
+			if input.valueRef != nil {
+				value = input.valueDeref()
+				if ref := input.secretRef(); ref != nil {
+					// the copy keeps its own secret so that
+					// it can be changed independently
+					if value, err = input.resolveSecret(*ref); err != nil {
+						return err
+					}
+				}
+				if err = field.SetValueRef(new(string)); err != nil {
+					return err
+				}
+				if err = field.setValue(copyValue(value), bindInput); err != nil {
+					return err
+				}
+				field.source = input.source
+			}
+			field.inputSet = input.inputSet
+
+		case *RepeatableGroup:
+			rg = target.fieldNameSet[name].(*RepeatableGroup)
+			for _, item := range input.items {
+				if itemCopy, err = rg.AddItem(); err != nil {
+					return err
+				}
+				if err = item.copyValues(itemCopy); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// flags the fields of the group and of the items of its
+// repeatable groups as having their input set if they
+// have values bound from the object they are bound to
+func (g *InputGroup) setBoundInputs() {
+
+	for _, i := range g.fieldNameSet {
+		switch input := i.(type) {
+
+		case *InputField:
+			input.inputSet = input.hasValue && input.source.Type != DefaultSource
+
+		case *RepeatableGroup:
+			for _, item := range input.items {
+				item.setBoundInputs()
+			}
+		}
+	}
+}
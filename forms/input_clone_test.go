@@ -0,0 +1,169 @@
+package forms_test
+
+import (
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goforms/test/mocks"
+	"github.com/mevansam/goutils/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	test_data "github.com/mevansam/goforms/test/data"
+)
+
+var _ = Describe("Input Group Clone", func() {
+
+	var (
+		err error
+
+		ig, clone *forms.InputGroup
+	)
+
+	It("copies the structure of a form", func() {
+
+		ig = test_data.NewTestInputCollection().Group("input-form")
+		field, err := ig.GetInputField("attrib12")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.SetInclusionFilter(`^value`, "must start with value")).To(Succeed())
+
+		clone, err = ig.Clone(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clone.String()).To(Equal(ig.String()))
+
+		input, err := forms.NewInputCursor(clone, "tag1").NextInput().GetCurrentInput()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(input.Name()).To(Equal("group1"))
+
+		clonedField, err := clone.GetInputField("attrib12")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clonedField).NotTo(BeIdenticalTo(field))
+		Expect(clonedField.SetValueRef(new(string))).To(Succeed())
+		Expect(clone.SetFieldValue("attrib12", "invalid")).To(MatchError("must start with value"))
+	})
+
+	It("copies values which can be changed independently", func() {
+
+		ig = forms.NewInputCollection().NewGroup("network", "network settings")
+		_, err = ig.NewInputField(forms.FieldAttributes{Name: "hostname", DisplayName: "Hostname"})
+		Expect(err).NotTo(HaveOccurred())
+		rg, err := ig.NewRepeatableGroup("interfaces", "Interface", "network interfaces", 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "name", DisplayName: "Name"},
+			{Name: "ip", DisplayName: "IP"},
+			{Name: "gateway", DisplayName: "Gateway"},
+		} {
+			_, err = rg.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		config := networkConfig{
+			Hostname: "gopher",
+			Interfaces: []networkInterface{
+				{Name: "eth0", IP: "10.0.0.2"},
+			},
+		}
+		Expect(ig.BindFields(&config)).To(Succeed())
+		for _, f := range append(ig.InputFields(), rg.Items()[0].InputFields()...) {
+			if f.Value() != nil {
+				f.SetInput()
+			}
+		}
+
+		clone, err = ig.Clone(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clone.InputValues()).To(Equal(ig.InputValues()))
+
+		Expect(clone.SetFieldValue("hostname", "gopher2")).To(Succeed())
+		Expect(config.Hostname).To(Equal("gopher"))
+		Expect(clone.InputValues()["hostname"]).To(Equal("gopher2"))
+	})
+
+	It("rebinds the copy to a new target", func() {
+
+		ig = forms.NewInputCollection().NewGroup("network", "network settings")
+		_, err = ig.NewInputField(forms.FieldAttributes{Name: "hostname", DisplayName: "Hostname"})
+		Expect(err).NotTo(HaveOccurred())
+		rg, err := ig.NewRepeatableGroup("interfaces", "Interface", "network interfaces", 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "name", DisplayName: "Name"},
+			{Name: "ip", DisplayName: "IP"},
+			{Name: "gateway", DisplayName: "Gateway"},
+		} {
+			_, err = rg.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(ig.BindFields(&networkConfig{Hostname: "gopher"})).To(Succeed())
+		for _, f := range ig.InputFields() {
+			f.SetInput()
+		}
+
+		target := networkConfig{
+			Hostname: "other",
+			Interfaces: []networkInterface{
+				{Name: "eth1", IP: "10.0.0.3"},
+			},
+		}
+		clone, err = ig.Clone(&target)
+		Expect(err).NotTo(HaveOccurred())
+
+		// only the values bound from
+		// the target have their input set
+		Expect(clone.InputValues()).To(Equal(map[string]string{
+			"hostname":   "other",
+			"interfaces": `[{"ip":"10.0.0.3","name":"eth1"}]`,
+		}))
+
+		cloneWithoutHost, err := ig.Clone(&networkConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cloneWithoutHost.InputValues()).To(BeEmpty())
+
+		value, err := clone.GetFieldValue("hostname")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*value).To(Equal("other"))
+		clonedGroup, err := clone.GetRepeatableGroup("interfaces")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clonedGroup.Items()).To(HaveLen(1))
+		Expect(clonedGroup.Items()[0].InputValues()).To(Equal(map[string]string{
+			"name": "eth1",
+			"ip":   "10.0.0.3",
+		}))
+
+		Expect(clone.SetFieldValue("hostname", "changed")).To(Succeed())
+		Expect(target.Hostname).To(Equal("changed"))
+		value, err = ig.GetFieldValue("hostname")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*value).To(Equal("gopher"))
+	})
+
+	It("is supported by input groups", func() {
+
+		var form forms.InputForm = forms.NewInputCollection().NewGroup("network", "network settings")
+		_, ok := form.(forms.CloneableForm)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("copies configurations built on a form", func() {
+
+		cfg := &mocks.FakeConfig{}
+		cfg.InitConfig("config", "a configuration")
+		cfg.AddInputField("user", "User", "the user", "", nil)
+		cfg.AddInputField("region", "Region", "the region", "us-east-1", nil)
+
+		form, err := cfg.InputForm()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(form.SetFieldValue("user", "gopher")).To(Succeed())
+
+		copied, err := cfg.Copy()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(copied.GetValue("user")).To(Equal(utils.PtrToStr("gopher")))
+		Expect(copied.GetValue("region")).To(Equal(utils.PtrToStr("us-east-1")))
+
+		copyForm, err := copied.InputForm()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(copyForm.SetFieldValue("user", "other")).To(Succeed())
+		value, _ := cfg.GetInternalValue("user")
+		Expect(*value).To(Equal("gopher"))
+	})
+})
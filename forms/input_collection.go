@@ -31,6 +31,10 @@ func (ic *InputCollection) NewGroup(
 		fieldNameSet: make(map[string]Input),
 
 		fieldValueLookupHints: make(map[string][]string),
+
+		localization: newLocalization(),
+		secrets:      &secrets{},
+		igMx:         &sync.RWMutex{},
 	}
 	ic.groups[name] = ig
 	return ig
@@ -260,6 +260,79 @@ var _ = Describe("Input Collection", func() {
 			Expect(reflect.DeepEqual(expectedValues, values)).To(BeTrue())
 		})
 
+		It("navigates back to previous inputs and undoes changes", func() {
+
+			cursor, err = forms.NewInputCursorFromCollection("input-form", ic, "tag1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cursor.PrevInput()).To(BeNil())
+			_, err = cursor.Undo()
+			Expect(err).To(HaveOccurred())
+
+			cursor = advanceCursorPositionAndValidate(cursor, "group1")
+			cursor, err = cursor.SetInput("attrib12", "value for attrib12 - B")
+			Expect(err).ToNot(HaveOccurred())
+			cursor = advanceCursorPositionAndValidate(cursor, "group2")
+			cursor, err = cursor.SetInput("attrib122", "value for attrib122")
+			Expect(err).ToNot(HaveOccurred())
+			cursor = advanceCursorPositionAndValidate(cursor, "attrib1221")
+			cursor, err = cursor.SetInput("attrib1221", "value for attrib1221")
+			Expect(err).ToNot(HaveOccurred())
+			cursor = advanceCursorPositionAndValidate(cursor, "attrib131")
+			cursor, err = cursor.SetInput("attrib131", "value for attrib131")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = cursor.Rewind("attrib14")
+			Expect(err).To(HaveOccurred())
+
+			// rewinding past attrib12 clears all its dependents
+			cursor, err = cursor.Rewind("attrib12")
+			Expect(err).ToNot(HaveOccurred())
+			input, err = cursor.GetCurrentInput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input.Name()).To(Equal("group1"))
+			Expect(ig.InputValues()).To(Equal(map[string]string{
+				"attrib12": "value for attrib12 - B",
+			}))
+
+			cursor, err = cursor.SetInput("attrib12", "value for attrib12 - A")
+			Expect(err).ToNot(HaveOccurred())
+			cursor = advanceCursorPositionAndValidate(cursor, "group2")
+			cursor, err = cursor.SetInput("attrib121", "value for attrib121")
+			Expect(err).ToNot(HaveOccurred())
+			cursor = advanceCursorPositionAndValidate(cursor, "attrib131")
+
+			cursor = cursor.PrevInput()
+			Expect(cursor).NotTo(BeNil())
+			input, err = cursor.GetCurrentInput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input.Name()).To(Equal("group2"))
+
+			cursor, err = cursor.Undo()
+			Expect(err).ToNot(HaveOccurred())
+			input, err = cursor.GetCurrentInput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input.Name()).To(Equal("group2"))
+			Expect(ig.InputValues()).To(Equal(map[string]string{
+				"attrib12": "value for attrib12 - A",
+			}))
+
+			cursor, err = cursor.Undo()
+			Expect(err).ToNot(HaveOccurred())
+			input, err = cursor.GetCurrentInput()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(input.Name()).To(Equal("group1"))
+			Expect(ig.InputValues()).To(Equal(map[string]string{
+				"attrib12": "value for attrib12 - B",
+			}))
+			Expect(cursor.PrevInput()).To(BeNil())
+
+			// the restored value is entered again to
+			// continue with the inputs that depend on it
+			cursor, err = cursor.SetInput("attrib12", "value for attrib12 - B")
+			Expect(err).ToNot(HaveOccurred())
+			cursor = advanceCursorPositionAndValidate(cursor, "group2")
+		})
+
 		It("navigates path option #3", func() {
 
 			expectedValues := map[string]string{
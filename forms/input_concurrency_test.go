@@ -0,0 +1,224 @@
+package forms_test
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mevansam/goforms/forms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Concurrent access to inputs", func() {
+
+	const (
+		numWorkers    = 8
+		numIterations = 200
+	)
+
+	var (
+		err error
+
+		ig *forms.InputGroup
+	)
+
+	BeforeEach(func() {
+		ig = forms.NewInputCollection().NewGroup("server", "server settings")
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "host", DisplayName: "Host"},
+			{Name: "port", DisplayName: "Port", InputType: forms.Number},
+			{Name: "token", DisplayName: "Token", Sensitive: true},
+		} {
+			_, err = ig.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(ig.BindFields(&struct {
+			Host  string  `form_field:"host"`
+			Port  int     `form_field:"port"`
+			Token *string `form_field:"token"`
+		}{})).To(Succeed())
+	})
+
+	It("sets and reads values from many goroutines", func() {
+
+		var (
+			wg sync.WaitGroup
+
+			errLock sync.Mutex
+			errs    []error
+		)
+
+		// listeners may access the form
+		// when they are notified
+		_, err = ig.OnChange("host", func(oldValue, newValue *string) {
+			_, _ = ig.GetFieldValue("port")
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		report := func(err error) {
+			if err != nil {
+				errLock.Lock()
+				errs = append(errs, err)
+				errLock.Unlock()
+			}
+		}
+
+		for w := 0; w < numWorkers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				// each worker adds a field of its own
+				name := fmt.Sprintf("worker%d", w)
+				_, err := ig.NewInputField(forms.FieldAttributes{Name: name, DisplayName: name})
+				report(err)
+				field, err := ig.GetInputField(name)
+				report(err)
+				report(field.SetValueRef(new(string)))
+				report(ig.AddFieldValueHint("host", fmt.Sprintf("file:///tmp/hosts%d", w)))
+
+				for i := 0; i < numIterations; i++ {
+					report(ig.SetFieldValue("host", fmt.Sprintf("host%d", i)))
+					report(ig.SetFieldValue("port", fmt.Sprintf("%d", 8000+i)))
+					report(ig.SetFieldValue("token", fmt.Sprintf("token%d", i)))
+					report(ig.SetFieldValue(name, fmt.Sprintf("%d", i)))
+					field.SetInput()
+
+					_, err = ig.GetFieldValue("host")
+					report(err)
+					_, _ = ig.GetFieldValueHints("host")
+					_ = ig.InputValues()
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		Expect(errs).To(BeEmpty())
+		Expect(ig.InputFields()).To(HaveLen(3 + numWorkers))
+		Expect(ig.InputValues()).To(HaveLen(numWorkers))
+
+		value, err := ig.GetFieldValue("port")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*value).To(Equal(fmt.Sprintf("%d", 8000+numIterations-1)))
+	})
+
+	It("sets and undoes values with cursors from many goroutines", func() {
+
+		var (
+			wg sync.WaitGroup
+
+			errLock sync.Mutex
+			errs    []error
+		)
+
+		report := func(err error) {
+			if err != nil {
+				errLock.Lock()
+				errs = append(errs, err)
+				errLock.Unlock()
+			}
+		}
+
+		for w := 0; w < numWorkers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				for i := 0; i < numIterations; i++ {
+					// each worker drives its own cursor
+					// over the form shared by all workers
+					cursor := forms.NewInputCursor(ig).NextInput()
+					cursor, err := cursor.SetInput("host", fmt.Sprintf("host%d-%d", w, i))
+					report(err)
+					cursor, err = cursor.NextInput().SetInput("port", fmt.Sprintf("%d", 8000+i))
+					report(err)
+					cursor, err = cursor.Undo()
+					report(err)
+					_, err = cursor.Undo()
+					report(err)
+
+					report(ig.SetFieldValue("token", fmt.Sprintf("token%d", i)))
+					_ = ig.InputValues()
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		Expect(errs).To(BeEmpty())
+	})
+
+	It("traverses inputs with cursors while inputs and items are added", func() {
+
+		var (
+			wg sync.WaitGroup
+
+			errLock sync.Mutex
+			errs    []error
+
+			numAdded int
+		)
+
+		report := func(err error) {
+			if err != nil {
+				errLock.Lock()
+				errs = append(errs, err)
+				errLock.Unlock()
+			}
+		}
+
+		rg, err := ig.NewRepeatableGroup("mounts", "Mounts", "volume mounts", 0, numWorkers)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rg.NewInputField(forms.FieldAttributes{Name: "path", DisplayName: "Path"})
+		Expect(err).NotTo(HaveOccurred())
+
+		mounts := []struct {
+			Path string `form_field:"path"`
+		}{}
+		Expect(rg.SetValueRef(&mounts)).To(Succeed())
+
+		for w := 0; w < numWorkers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				// each worker adds a field of its own and
+				// tries to add more items than are allowed
+				name := fmt.Sprintf("worker%d", w)
+				_, err := ig.NewInputField(forms.FieldAttributes{Name: name, DisplayName: name})
+				report(err)
+				for i := 0; i < 2; i++ {
+					if _, err := rg.AddItem(); err == nil {
+						errLock.Lock()
+						numAdded++
+						errLock.Unlock()
+					} else {
+						Expect(err).To(MatchError(fmt.Sprintf("no more than %d item(s) can be added to 'mounts'", numWorkers)))
+					}
+				}
+
+				for i := 0; i < numIterations/10; i++ {
+					cursor := forms.NewInputCursor(ig).NextInput()
+					for cursor != nil {
+						_, err = cursor.GetCurrentInput()
+						report(err)
+						cursor = cursor.NextInput()
+					}
+					_ = ig.EnabledInputs(true)
+					_, err = ig.GetRepeatableGroup("mounts")
+					report(err)
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		Expect(errs).To(BeEmpty())
+		Expect(numAdded).To(Equal(numWorkers))
+		Expect(rg.Items()).To(HaveLen(numWorkers))
+		Expect(mounts).To(HaveLen(numWorkers))
+		Expect(ig.Inputs()).To(HaveLen(4 + numWorkers))
+	})
+})
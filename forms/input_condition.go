@@ -0,0 +1,831 @@
+package forms
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// A depends on condition of a field which needs
+// to be satisfied for the field to be enabled.
+type postCondition struct {
+	expr conditionExpr
+	// fields referenced by the condition
+	fields []*InputField
+}
+
+// out: whether any of the fields referenced by the
+//      condition has a value. a condition is only
+//      evaluated once it applies which allows fields
+//      to depend on alternative fields of a container
+func (c postCondition) applies() bool {
+	for _, f := range c.fields {
+		if f.Value() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// An expression in a depends on condition
+type conditionExpr interface {
+	// out: whether the expression is satisfied
+	//      by the current values of its fields
+	eval() bool
+	// out: whether the expression can be satisfied
+	//      by the values its fields accept. this is
+	//      false if a comparison of a field with
+	//      accepted values excludes all of them.
+	satisfiable() bool
+	// out: a json schema which a document satisfies
+	//      if it satisfies the expression
+	jsonSchema() map[string]interface{}
+
+	String() string
+}
+
+type orExpr struct {
+	terms []conditionExpr
+}
+
+func (e *orExpr) eval() bool {
+	for _, t := range e.terms {
+		if t.eval() {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *orExpr) satisfiable() bool {
+	for _, t := range e.terms {
+		if t.satisfiable() {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *orExpr) jsonSchema() map[string]interface{} {
+	return map[string]interface{}{"anyOf": termSchemas(e.terms)}
+}
+
+func (e *orExpr) String() string {
+	return joinTerms(e.terms, " or ")
+}
+
+type andExpr struct {
+	terms []conditionExpr
+}
+
+func (e *andExpr) eval() bool {
+	for _, t := range e.terms {
+		if !t.eval() {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *andExpr) satisfiable() bool {
+
+	// terms comparing the same field must
+	// be satisfied by the same value
+	comparisons := make(map[*InputField][]fieldComparison)
+	for _, t := range e.terms {
+		if !t.satisfiable() {
+			return false
+		}
+		if c, ok := t.(fieldComparison); ok {
+			comparisons[c.comparedField()] = append(comparisons[c.comparedField()], c)
+		}
+	}
+	for field, terms := range comparisons {
+		if len(terms) > 1 && !satisfiableTogether(field, terms) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *andExpr) jsonSchema() map[string]interface{} {
+	return map[string]interface{}{"allOf": termSchemas(e.terms)}
+}
+
+func (e *andExpr) String() string {
+	return joinTerms(e.terms, " and ")
+}
+
+type notExpr struct {
+	expr conditionExpr
+}
+
+func (e *notExpr) eval() bool {
+	return !e.expr.eval()
+}
+
+func (e *notExpr) satisfiable() bool {
+	return true
+}
+
+func (e *notExpr) jsonSchema() map[string]interface{} {
+	return map[string]interface{}{"not": e.expr.jsonSchema()}
+}
+
+func (e *notExpr) String() string {
+	return "not " + termString(e.expr)
+}
+
+// An expression comparing the value of a single field
+type fieldComparison interface {
+	// out: the field whose value is compared
+	comparedField() *InputField
+	// out: the values one of which the field's value
+	//      must equal to satisfy the expression. nil
+	//      if the expression is satisfied by others.
+	requiredValues() []string
+	// in: value - a value of the field
+	// out: whether the value satisfies the expression
+	match(value string) bool
+}
+
+// satisfied when the field has a value
+type hasValueExpr struct {
+	field *InputField
+}
+
+func (e *hasValueExpr) eval() bool {
+	return e.field.Value() != nil
+}
+
+func (e *hasValueExpr) satisfiable() bool {
+	return true
+}
+
+func (e *hasValueExpr) jsonSchema() map[string]interface{} {
+	return map[string]interface{}{"required": []string{e.field.name}}
+}
+
+func (e *hasValueExpr) String() string {
+	return e.field.name
+}
+
+// satisfied when the field's value is
+// one of (or none of) the given values
+type inExpr struct {
+	field  *InputField
+	values []string
+	negate bool
+}
+
+func (e *inExpr) eval() bool {
+
+	value := e.field.Value()
+	return value != nil && e.match(*value)
+}
+
+func (e *inExpr) satisfiable() bool {
+	return acceptsMatch(e.field, e.match)
+}
+
+func (e *inExpr) comparedField() *InputField {
+	return e.field
+}
+
+func (e *inExpr) requiredValues() []string {
+	if e.negate {
+		return nil
+	}
+	return e.values
+}
+
+// in: value - a value of the field
+// out: whether the value satisfies the expression
+func (e *inExpr) match(value string) bool {
+
+	for _, v := range e.values {
+		if valuesEqual(value, v) {
+			return !e.negate
+		}
+	}
+	return e.negate
+}
+
+func (e *inExpr) jsonSchema() map[string]interface{} {
+
+	enum := make([]interface{}, 0, len(e.values))
+	for _, v := range e.values {
+		enum = append(enum, e.field.jsonSchemaValue(v))
+	}
+	property := map[string]interface{}{"enum": enum}
+	if e.negate {
+		property = map[string]interface{}{"not": property}
+	}
+	return fieldSchema(e.field, property)
+}
+
+func (e *inExpr) String() string {
+
+	if len(e.values) == 1 {
+		op := "=="
+		if e.negate {
+			op = "!="
+		}
+		return (&compareExpr{field: e.field, op: op, value: e.values[0]}).String()
+	}
+
+	values := make([]string, 0, len(e.values))
+	for _, v := range e.values {
+		values = append(values, strconv.Quote(v))
+	}
+	op := " in ("
+	if e.negate {
+		op = " not in ("
+	}
+	return e.field.name + op + strings.Join(values, ", ") + ")"
+}
+
+// satisfied when the field's value compares
+// to the given value using the operator
+type compareExpr struct {
+	field *InputField
+	op    string
+	value string
+
+	pattern *regexp.Regexp
+}
+
+func (e *compareExpr) eval() bool {
+
+	value := e.field.Value()
+	return value != nil && e.match(*value)
+}
+
+func (e *compareExpr) satisfiable() bool {
+	return acceptsMatch(e.field, e.match)
+}
+
+func (e *compareExpr) comparedField() *InputField {
+	return e.field
+}
+
+func (e *compareExpr) requiredValues() []string {
+	if e.op != "==" {
+		return nil
+	}
+	return []string{e.value}
+}
+
+// in: value - a value of the field
+// out: whether the value satisfies the expression
+func (e *compareExpr) match(value string) bool {
+
+	var (
+		err  error
+		x, y float64
+	)
+
+	switch e.op {
+	case "==":
+		return valuesEqual(value, e.value)
+	case "!=":
+		return !valuesEqual(value, e.value)
+	case "=~":
+		return e.pattern.MatchString(value)
+	case "!~":
+		return !e.pattern.MatchString(value)
+	}
+
+	// remaining operators compare numbers
+	if x, err = strconv.ParseFloat(value, 64); err != nil {
+		return false
+	}
+	if y, err = strconv.ParseFloat(e.value, 64); err != nil {
+		return false
+	}
+	switch e.op {
+	case "<":
+		return x < y
+	case "<=":
+		return x <= y
+	case ">":
+		return x > y
+	case ">=":
+		return x >= y
+	}
+	return false
+}
+
+func (e *compareExpr) jsonSchema() map[string]interface{} {
+
+	var (
+		property map[string]interface{}
+	)
+
+	// patterns only apply to string values and
+	// ranges only to number values. comparisons
+	// that do not apply to the field's values
+	// only require the field to be present.
+	property = map[string]interface{}{}
+	isNumber := e.field.inputType == Number
+
+	n, _ := strconv.ParseFloat(e.value, 64)
+	switch {
+	case e.op == "==":
+		property["enum"] = []interface{}{e.field.jsonSchemaValue(e.value)}
+	case e.op == "!=":
+		property["not"] = map[string]interface{}{"enum": []interface{}{e.field.jsonSchemaValue(e.value)}}
+	case e.op == "=~" && !isNumber:
+		property["pattern"] = e.value
+	case e.op == "!~" && !isNumber:
+		property["not"] = map[string]interface{}{"pattern": e.value}
+	case e.op == "<" && isNumber:
+		property["exclusiveMaximum"] = n
+	case e.op == "<=" && isNumber:
+		property["maximum"] = n
+	case e.op == ">" && isNumber:
+		property["exclusiveMinimum"] = n
+	case e.op == ">=" && isNumber:
+		property["minimum"] = n
+	}
+	return fieldSchema(e.field, property)
+}
+
+func (e *compareExpr) String() string {
+
+	value := e.value
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		value = strconv.Quote(value)
+	}
+	return e.field.name + " " + e.op + " " + value
+}
+
+// in: field    - field a schema is being created for
+// in: property - schema of the field's value
+// out: a schema requiring the field with the given value
+func fieldSchema(field *InputField, property map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"properties": map[string]interface{}{field.name: property},
+		"required":   []string{field.name},
+	}
+}
+
+// in: field - a field referenced by an expression
+// in: match - whether a value satisfies the expression
+// out: whether any value the field accepts satisfies
+//      the expression. true if all values are accepted.
+func acceptsMatch(field *InputField, match func(value string) bool) bool {
+
+	if len(field.acceptedValues) == 0 || field.listInput {
+		return true
+	}
+	for _, v := range field.acceptedValues {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// in: field - a field compared by all the terms
+// in: terms - terms of an and expression comparing the field
+// out: whether a value of the field satisfies all the terms.
+//      the values tried are the values the field accepts or
+//      else the values the terms require. if there are none
+//      the terms are assumed to be satisfiable together.
+func satisfiableTogether(field *InputField, terms []fieldComparison) bool {
+
+	var (
+		candidates []string
+	)
+
+	if len(field.acceptedValues) > 0 && !field.listInput {
+		candidates = field.acceptedValues
+	} else {
+		for _, t := range terms {
+			candidates = append(candidates, t.requiredValues()...)
+		}
+		if len(candidates) == 0 {
+			return true
+		}
+	}
+	for _, v := range candidates {
+		matched := true
+		for _, t := range terms {
+			if !t.match(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// in: value - the value of a field
+// in: other - the value to compare with
+// out: whether the values are equal. values that
+//      are both numbers are compared numerically
+func valuesEqual(value, other string) bool {
+
+	if value == other {
+		return true
+	}
+	x, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	y, err := strconv.ParseFloat(other, 64)
+	return err == nil && x == y
+}
+
+func termSchemas(terms []conditionExpr) []interface{} {
+
+	schemas := make([]interface{}, 0, len(terms))
+	for _, t := range terms {
+		schemas = append(schemas, t.jsonSchema())
+	}
+	return schemas
+}
+
+func joinTerms(terms []conditionExpr, op string) string {
+
+	s := make([]string, 0, len(terms))
+	for _, t := range terms {
+		s = append(s, termString(t))
+	}
+	return strings.Join(s, op)
+}
+
+// out: the expression enclosed in parenthesis
+//      if it combines more than one term
+func termString(e conditionExpr) string {
+
+	switch e.(type) {
+	case *orExpr, *andExpr:
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+// matches depends on conditions of the original
+// format "name" or "name=value1|value2"
+var simpleCondition = regexp.MustCompile(`^\s*([A-Za-z_][\w.\-]*)\s*(?:=([^=!<>"']*))?$`)
+
+// matches the keywords combining the terms of an
+// expression. values of the original format may
+// contain spaces so a condition with values that
+// contain these keywords is parsed as an expression.
+var conditionKeyword = regexp.MustCompile(`\s(?:and|or)\s`)
+
+// parses a depends on condition. conditions of the format
+// "name" and "name=value1|value2" where the values are not
+// quoted are parsed as before expressions were supported
+// unless the values contain "and" or "or" between spaces.
+// all other conditions are parsed as expressions.
+//
+// in: condition - the condition to parse
+// in: lookup    - returns the field with the given name
+// out: the parsed expression and the fields it references
+//      in the order they were added to the form. the
+//      expression is nil if the condition only requires
+//      that a field has a value.
+func parseCondition(
+	condition string,
+	lookup func(name string) (*InputField, error),
+) (conditionExpr, []*InputField, error) {
+
+	var (
+		err error
+
+		field *InputField
+		expr  conditionExpr
+	)
+
+	if m := simpleCondition.FindStringSubmatch(condition); m != nil && !conditionKeyword.MatchString(m[2]) {
+		if field, err = lookup(m[1]); err != nil {
+			return nil, nil, err
+		}
+		if len(m[2]) == 0 {
+			return nil, []*InputField{field}, nil
+		}
+		return &inExpr{
+			field:  field,
+			values: strings.Split(m[2], "|"),
+		}, []*InputField{field}, nil
+	}
+
+	p := &conditionParser{
+		lookup: lookup,
+		fields: make(map[string]*InputField),
+	}
+	if p.tokens, err = tokenizeCondition(condition); err != nil {
+		return nil, nil, err
+	}
+	if expr, err = p.parseOr(); err != nil {
+		return nil, nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, nil, fmt.Errorf("unexpected '%s'", p.tokens[p.pos].text)
+	}
+
+	fields := make([]*InputField, 0, len(p.fields))
+	for _, f := range p.fields {
+		fields = append(fields, f)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].order < fields[j].order
+	})
+	return expr, fields, nil
+}
+
+type tokenKind int
+
+const (
+	identToken tokenKind = iota
+	stringToken
+	numberToken
+	operatorToken
+)
+
+type conditionToken struct {
+	kind tokenKind
+	text string
+}
+
+// in: condition - the condition expression
+// out: the tokens of the expression
+func tokenizeCondition(condition string) ([]conditionToken, error) {
+
+	var (
+		err    error
+		tokens []conditionToken
+	)
+
+	s := []rune(condition)
+	for i := 0; i < len(s); {
+
+		c := s[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(s) && s[j] != c {
+				if s[j] == '\\' && c == '"' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("unterminated string in condition '%s'", condition)
+			}
+			text := string(s[i+1 : j])
+			if c == '"' {
+				if text, err = strconv.Unquote(string(s[i : j+1])); err != nil {
+					return nil, fmt.Errorf("invalid string in condition '%s': %w", condition, err)
+				}
+			}
+			tokens = append(tokens, conditionToken{stringToken, text})
+			i = j + 1
+
+		case unicode.IsDigit(c) || (c == '-' && i+1 < len(s) && unicode.IsDigit(s[i+1])):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(s[j]) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, conditionToken{numberToken, string(s[i:j])})
+			i = j
+
+		case unicode.IsLetter(c) || c == '_':
+			j := i + 1
+			for j < len(s) && (unicode.IsLetter(s[j]) || unicode.IsDigit(s[j]) || strings.ContainsRune("_.-", s[j])) {
+				j++
+			}
+			tokens = append(tokens, conditionToken{identToken, string(s[i:j])})
+			i = j
+
+		case strings.ContainsRune("(),", c):
+			tokens = append(tokens, conditionToken{operatorToken, string(c)})
+			i++
+
+		case strings.ContainsRune("=!<>", c):
+			j := i + 1
+			if j < len(s) && strings.ContainsRune("=~", s[j]) {
+				j++
+			}
+			op := string(s[i:j])
+			switch op {
+			case "=":
+				op = "=="
+			case "!", "<~", ">~":
+				return nil, fmt.Errorf("invalid operator '%s' in condition '%s'", op, condition)
+			}
+			tokens = append(tokens, conditionToken{operatorToken, op})
+			i = j
+
+		default:
+			return nil, fmt.Errorf("unexpected character '%c' in condition '%s'", c, condition)
+		}
+	}
+	return tokens, nil
+}
+
+// A recursive descent parser of condition expressions
+//
+//   or         := and { "or" and }
+//   and        := not { "and" not }
+//   not        := "not" not | "(" or ")" | comparison
+//   comparison := name [ op value | [ "not" ] "in" "(" value { "," value } ")" ]
+//   op         := "=" | "==" | "!=" | "=~" | "!~" | "<" | "<=" | ">" | ">="
+//   value      := string | number | "true" | "false"
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+
+	lookup func(name string) (*InputField, error)
+	fields map[string]*InputField
+}
+
+// out: the next token without consuming it
+func (p *conditionParser) peek() *conditionToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+// in: kind - kind of token to match
+// in: text - text of the token to match
+// out: whether the next token matched and was consumed
+func (p *conditionParser) accept(kind tokenKind, text string) bool {
+	if t := p.peek(); t != nil && t.kind == kind && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// in: text - text of the operator expected next
+func (p *conditionParser) expect(text string) error {
+	if !p.accept(operatorToken, text) {
+		return p.unexpected(fmt.Sprintf("'%s'", text))
+	}
+	return nil
+}
+
+// in: expected - description of what was expected
+func (p *conditionParser) unexpected(expected string) error {
+	if t := p.peek(); t != nil {
+		return fmt.Errorf("expected %s but found '%s'", expected, t.text)
+	}
+	return fmt.Errorf("expected %s at end of condition", expected)
+}
+
+func (p *conditionParser) parseOr() (conditionExpr, error) {
+
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := []conditionExpr{expr}
+	for p.accept(identToken, "or") {
+		if expr, err = p.parseAnd(); err != nil {
+			return nil, err
+		}
+		terms = append(terms, expr)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &orExpr{terms: terms}, nil
+}
+
+func (p *conditionParser) parseAnd() (conditionExpr, error) {
+
+	expr, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	terms := []conditionExpr{expr}
+	for p.accept(identToken, "and") {
+		if expr, err = p.parseNot(); err != nil {
+			return nil, err
+		}
+		terms = append(terms, expr)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return &andExpr{terms: terms}, nil
+}
+
+func (p *conditionParser) parseNot() (conditionExpr, error) {
+
+	if p.accept(identToken, "not") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	}
+	if p.accept(operatorToken, "(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *conditionParser) parseComparison() (conditionExpr, error) {
+
+	var (
+		err error
+
+		field  *InputField
+		value  string
+		values []string
+	)
+
+	t := p.peek()
+	if t == nil || t.kind != identToken {
+		return nil, p.unexpected("a field name")
+	}
+	p.pos++
+	if field, err = p.lookup(t.text); err != nil {
+		return nil, err
+	}
+	p.fields[field.name] = field
+
+	negate := p.accept(identToken, "not")
+	if p.accept(identToken, "in") {
+		if err = p.expect("("); err != nil {
+			return nil, err
+		}
+		for {
+			if value, err = p.parseValue(); err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if !p.accept(operatorToken, ",") {
+				break
+			}
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+		return &inExpr{field: field, values: values, negate: negate}, nil
+	}
+	if negate {
+		return nil, p.unexpected("'in'")
+	}
+
+	op := p.peek()
+	if op == nil || op.kind != operatorToken || strings.ContainsAny(op.text, "(),") {
+		// a field name on its own is satisfied
+		// when the field has a value
+		return &hasValueExpr{field: field}, nil
+	}
+	p.pos++
+	if value, err = p.parseValue(); err != nil {
+		return nil, err
+	}
+
+	expr := &compareExpr{field: field, op: op.text, value: value}
+	switch op.text {
+	case "=~", "!~":
+		if expr.pattern, err = regexp.Compile(value); err != nil {
+			return nil, err
+		}
+	case "<", "<=", ">", ">=":
+		if _, err = strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf(
+				"operator '%s' requires a number but found '%s'",
+				op.text, value)
+		}
+	}
+	return expr, nil
+}
+
+func (p *conditionParser) parseValue() (string, error) {
+
+	t := p.peek()
+	if t != nil && (t.kind == stringToken || t.kind == numberToken ||
+		(t.kind == identToken && (t.text == "true" || t.text == "false"))) {
+
+		p.pos++
+		return t.text, nil
+	}
+	return "", p.unexpected("a value")
+}
@@ -0,0 +1,167 @@
+package forms_test
+
+import (
+	"fmt"
+
+	"github.com/mevansam/goforms/forms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Depends On Conditions", func() {
+
+	var (
+		err error
+
+		ig *forms.InputGroup
+	)
+
+	BeforeEach(func() {
+
+		ig = forms.NewInputCollection().NewGroup("cluster", "cluster settings")
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "provider", AcceptedValues: []string{"aws", "azure", "gcp"}},
+			{Name: "region"},
+			{Name: "instance_count", InputType: forms.Number},
+			{
+				Name:      "gpu_type",
+				DependsOn: []string{`provider in ("aws", "gcp") and instance_count >= 2`},
+			},
+			{
+				Name:      "zone",
+				DependsOn: []string{`region =~ "^us-" and not provider == 'azure'`},
+			},
+			{
+				Name:      "spot",
+				DependsOn: []string{`provider != "azure" or (region = "eastus")`},
+			},
+			{
+				Name:      "tier",
+				DependsOn: []string{"provider=aws|gcp", `region !~ "^eu-"`},
+			},
+		} {
+			_, err = ig.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		for _, f := range ig.InputFields() {
+			err = f.SetValueRef(new(string))
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	enabled := func() map[string]bool {
+		enabled := make(map[string]bool)
+		for _, name := range []string{"gpu_type", "zone", "spot", "tier"} {
+			f, err := ig.GetInputField(name)
+			Expect(err).NotTo(HaveOccurred())
+			enabled[name] = f.Enabled(true)
+		}
+		return enabled
+	}
+
+	setValues := func(provider, region, count string) {
+		Expect(ig.SetFieldValue("provider", provider)).To(Succeed())
+		Expect(ig.SetFieldValue("region", region)).To(Succeed())
+		Expect(ig.SetFieldValue("instance_count", count)).To(Succeed())
+	}
+
+	It("evaluates the whole expression of every condition", func() {
+
+		// conditions do not apply until the
+		// fields they refer to have values
+		Expect(enabled()).To(Equal(map[string]bool{
+			"gpu_type": true, "zone": true, "spot": true, "tier": true,
+		}))
+
+		setValues("aws", "us-east-1", "1")
+		Expect(enabled()).To(Equal(map[string]bool{
+			"gpu_type": false, "zone": true, "spot": true, "tier": true,
+		}))
+
+		setValues("gcp", "eu-west-1", "4.0")
+		Expect(enabled()).To(Equal(map[string]bool{
+			"gpu_type": true, "zone": false, "spot": true, "tier": false,
+		}))
+
+		setValues("azure", "eastus", "4")
+		Expect(enabled()).To(Equal(map[string]bool{
+			"gpu_type": false, "zone": false, "spot": true, "tier": false,
+		}))
+
+		setValues("azure", "westus", "4")
+		Expect(enabled()).To(Equal(map[string]bool{
+			"gpu_type": false, "zone": false, "spot": false, "tier": false,
+		}))
+	})
+
+	It("places fields after the last field their conditions refer to", func() {
+
+		f, err := ig.GetInputField("instance_count")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(f.Inputs())).To(Equal(1))
+		Expect(f.Inputs()[0].Name()).To(Equal("gpu_type"))
+
+		f, err = ig.GetInputField("region")
+		Expect(err).NotTo(HaveOccurred())
+		names := []string{}
+		for _, i := range f.Inputs() {
+			names = append(names, i.Name())
+		}
+		Expect(names).To(Equal([]string{"zone", "spot", "tier"}))
+
+		Expect(ig.String()).To(ContainSubstring(
+			`gpu_type : conditions[provider in ("aws", "gcp") and instance_count >= 2]`))
+	})
+
+	It("maps conditions to json schema rules", func() {
+
+		rules := ig.JSONSchema()["allOf"].([]interface{})
+		Expect(rules).To(ContainElement(map[string]interface{}{
+			"if": map[string]interface{}{
+				"anyOf": []interface{}{
+					map[string]interface{}{
+						"properties": map[string]interface{}{
+							"provider": map[string]interface{}{
+								"not": map[string]interface{}{"enum": []interface{}{"azure"}},
+							},
+						},
+						"required": []string{"provider"},
+					},
+					map[string]interface{}{
+						"properties": map[string]interface{}{
+							"region": map[string]interface{}{"enum": []interface{}{"eastus"}},
+						},
+						"required": []string{"region"},
+					},
+				},
+			},
+			"then": map[string]interface{}{"required": []string{"spot"}},
+			"else": map[string]interface{}{
+				"not": map[string]interface{}{"required": []string{"spot"}},
+			},
+		}))
+	})
+
+	It("rejects invalid conditions", func() {
+
+		for i, condition := range []string{
+			`provider ==`,
+			`provider == "aws" and`,
+			`(provider == "aws"`,
+			`unknown == "aws"`,
+			`instance_count > "many"`,
+			`region =~ "["`,
+			`provider not == "aws"`,
+			`provider == "aws`,
+			`provider=aws and region`,
+			`provider=aws or region=us-east-1`,
+		} {
+			_, err = ig.NewInputField(forms.FieldAttributes{
+				Name:      fmt.Sprintf("invalid%d", i),
+				DependsOn: []string{condition},
+			})
+			Expect(err).To(HaveOccurred(), condition)
+		}
+	})
+})
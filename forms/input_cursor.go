@@ -1,12 +1,27 @@
 package forms
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+)
 
 type InputCursor struct {
 	parents []*InputCursor
 	group   Input
 	index   int
 
+	// whether the cursor is stepping
+	// through an item of a repeatable
+	// group
+	repeating bool
+
+	// inputs visited and values set by
+	// this cursor and the cursors derived
+	// from it
+	history *cursorHistory
+	// identifies the cursor within its history
+	id int
+
 	tags []string
 }
 
@@ -31,29 +46,46 @@ func NewInputCursor(
 	tags ...string,
 ) *InputCursor {
 
-	return &InputCursor{
+	history := &cursorHistory{}
+	return history.register(&InputCursor{
 		parents: []*InputCursor{},
 		group:   input,
 		index:   -1,
 
+		history: history,
+
 		tags: tags,
-	}
+	})
 }
 
 // advances the cursor to the next input
 func (c *InputCursor) NextInput() *InputCursor {
 
+	c.history.record(cursorStep{Op: nextStep, Cursor: c.id})
+
+	cursor := c.next()
+	if cursor != nil {
+		c.history.positions = append(c.history.positions, cursor.position())
+	}
+	return cursor
+}
+
+// out: the cursor at the next input
+func (c *InputCursor) next() *InputCursor {
+
 	numInputs := len(c.group.Inputs())
 	if c.index >= 0 && c.index < numInputs {
 		currInput := c.group.Inputs()[c.index]
-		if currInput.Type() != Container && len(currInput.Inputs()) > 0 {
+		if currInput.Type() != Container && currInput.Type() != Repeatable && len(currInput.Inputs()) > 0 {
 			// curr input has dependents. so update 
 			// cursor to point to the dependents.
-			return &InputCursor{
+			return c.history.register(&InputCursor{
 				parents: append([]*InputCursor{c}, c.parents...),
 				group:   currInput,
 				index:   0,
-			}
+
+				history: c.history,
+			})
 		}
 	}
 
@@ -68,6 +100,12 @@ func (c *InputCursor) NextInput() *InputCursor {
 				cursor = nil
 				break
 			}
+			if cursor.repeating {
+				// return to the repeatable group at the
+				// parent so that more items can be added
+				cursor = cursor.parents[0]
+				break
+			}
 			// resume cursor at parent
 			cursor = cursor.parents[0]
 
@@ -94,7 +132,76 @@ func (c *InputCursor) GetCurrentInput() (Input, error) {
 // in: value - value to set. if nil default value will be used.
 // out: c
 func (c *InputCursor) SetInput(name, value string) (*InputCursor, error) {
-	return c.setInput(name, &value)
+
+	cursor, field, err := c.setInput(name, &value, nil)
+	if err == nil {
+		c.history.record(cursorStep{
+			Op: setStep, Cursor: c.id, Name: name, Value: &value,
+			sensitive: field.Sensitive(),
+		})
+	}
+	return cursor, err
+}
+
+// sets value of input at current cursor position to
+// a value chosen from the field's hints and updates
+// state if input has dependent inputs. the value is
+// reported as sourced from the field's hints.
+//
+// in: name - of input to set value of
+// in: value - one of the values returned by GetFieldValueHints()
+// out: c
+func (c *InputCursor) SetInputFromHint(name, value string) (*InputCursor, error) {
+	return c.setSourcedInput(name, value, ValueSource{Type: HintSource})
+}
+
+// sets value of input at current cursor position to
+// the value of the first of the field's environment
+// variables that is set and updates state if input
+// has dependent inputs. the value is reported as
+// sourced from the environment unless the field's
+// value is read from the file the variable refers to.
+//
+// in: name - of input to set value of
+// out: c
+func (c *InputCursor) SetInputFromEnv(name string) (*InputCursor, error) {
+
+	var (
+		err error
+
+		inputField *InputField
+	)
+
+	if inputField, err = c.currentField(name); err != nil {
+		return c, err
+	}
+	for _, e := range inputField.EnvVars() {
+		if value, exists := os.LookupEnv(e); exists {
+			if valueFromFile, _ := inputField.ValueFromFile(); valueFromFile {
+				return c.SetInput(name, value)
+			}
+			return c.setSourcedInput(name, value, ValueSource{Type: EnvSource, Name: e})
+		}
+	}
+	return c, fmt.Errorf(
+		"none of the environment variables of input '%s' are set",
+		name)
+}
+
+// in: name   - of input to set value of
+// in: value  - value to set
+// in: source - where the value came from
+// out: c
+func (c *InputCursor) setSourcedInput(name, value string, source ValueSource) (*InputCursor, error) {
+
+	cursor, field, err := c.setInput(name, &value, &source)
+	if err == nil {
+		c.history.record(cursorStep{
+			Op: setStep, Cursor: c.id, Name: name, Value: &value, Source: &source,
+			sensitive: field.Sensitive(),
+		})
+	}
+	return cursor, err
 }
 
 // sets default value of input at current cursor position
@@ -103,7 +210,12 @@ func (c *InputCursor) SetInput(name, value string) (*InputCursor, error) {
 // in: name - of input to set value of
 // out: c
 func (c *InputCursor) SetDefaultInput(name string) (*InputCursor, error) {
-	return c.setInput(name, nil)
+
+	cursor, _, err := c.setInput(name, nil, nil)
+	if err == nil {
+		c.history.record(cursorStep{Op: setStep, Cursor: c.id, Name: name})
+	}
+	return cursor, err
 }
 
 // sets value of input at current cursor position
@@ -111,25 +223,79 @@ func (c *InputCursor) SetDefaultInput(name string) (*InputCursor, error) {
 //
 // in: name - of input to set value of
 // in: value - value to set. if nil default value will be used.
+// in: source - where the value came from. if nil the value is
+//              bound or read from the file it refers to.
 // out: c
-func (c *InputCursor) setInput(name string, value *string) (*InputCursor, error) {
+// out: the field whose value was set
+func (c *InputCursor) setInput(name string, value *string, source *ValueSource) (*InputCursor, *InputField, error) {
 
 	var (
 		err error
 
 		cursor *InputCursor
-		inputs []Input
-
-		currInput,
-		selectedInput Input
 
 		inputField *InputField
+		change     valueChange
 	)
 
 	cursor = c
-	inputs = cursor.group.Inputs()
-	currInput = inputs[cursor.index]
+	if inputField, err = c.currentField(name); err != nil {
+		return cursor, nil, err
+	}
+	if !inputField.Enabled(true, c.tags...) {
+		return cursor, nil, fmt.Errorf(
+			"input field '%s' is disabled", name)
+	}
+
+	if change, err = c.newValueChange(inputField); err != nil {
+		return cursor, nil, err
+	}
+
+	inputField.SetInput()
+	if value != nil && source != nil {
+		if err = inputField.setSourcedValue(value, bindInput, *source); err != nil {
+			return cursor, nil, err
+		}
+
+	} else if value != nil {
+		if err = inputField.SetValue(value); err != nil {
+			return cursor, nil, err
+		}
+
+	} else if !inputField.HasValue() {
+		return cursor, nil, fmt.Errorf(
+			"no default value for input name '%s' could be determined",
+			name)
+	}
+	c.history.changes = append(c.history.changes, change)
+
+	if len(inputField.Inputs()) > 0 {
 
+		// input for which value was set has dependents.
+		// so update cursor to point to the dependents.
+		cursor = c.history.register(&InputCursor{
+			parents: append([]*InputCursor{c}, c.parents...),
+			group:   inputField,
+			index:   -1,
+
+			history: c.history,
+		})
+	}
+	return cursor, inputField, nil
+}
+
+// in: name - of the input to set the value of
+// out: the field at the current cursor position or the
+//      field of the container at the position with the
+//      given name
+func (c *InputCursor) currentField(name string) (*InputField, error) {
+
+	var (
+		currInput,
+		selectedInput Input
+	)
+
+	currInput = c.group.Inputs()[c.index]
 	if currInput.Type() == Container {
 
 		selectedInput = nil
@@ -141,46 +307,362 @@ func (c *InputCursor) setInput(name string, value *string) (*InputCursor, error)
 			}
 		}
 		if selectedInput == nil {
-			return cursor, fmt.Errorf(
+			return nil, fmt.Errorf(
 				"unable to find input '%s' within 'Container' of mutually exclusive inputs '%s",
-				name, cursor.group.Inputs()[cursor.index].Name())
+				name, currInput.Name())
 		}
 		currInput = selectedInput
 
 	} else if name != currInput.Name() {
 
-		return cursor, fmt.Errorf(
+		return nil, fmt.Errorf(
 			"cursor is at input '%s' which is different from provided input name '%s' to set value of",
 			currInput.Name(), name)
 	}
+	return currInput.(*InputField), nil
+}
 
-	inputField = currInput.(*InputField)
-	if !inputField.Enabled(true, c.tags...) {
-		return cursor, fmt.Errorf(
-			"input field '%s' is disabled", name)
+// adds an item to the repeatable group at the current
+// cursor position and returns a cursor which steps
+// through the inputs of the new item. once all the
+// item's inputs have been stepped through the cursor
+// returns to the repeatable group.
+//
+// in: name - of the repeatable group to add an item to
+// out: cursor for the new item
+func (c *InputCursor) AddItem(name string) (*InputCursor, error) {
+
+	var (
+		err error
+
+		rg   *RepeatableGroup
+		item *InputGroup
+	)
+
+	if rg, err = c.currentRepeatableGroup(name); err != nil {
+		return c, err
+	}
+	if item, err = rg.AddItem(); err != nil {
+		return c, err
 	}
+	c.history.record(cursorStep{Op: addItemStep, Cursor: c.id, Name: name})
+	return c.itemCursor(item), nil
+}
 
-	inputField.SetInput()
-	if value != nil {
-		if err = inputField.SetValue(value); err != nil {
-			return cursor, err
-		}
+// returns a cursor which steps through the inputs of
+// an existing item of the repeatable group at the
+// current cursor position
+//
+// in: name  - of the repeatable group
+// in: index - of the item to step through
+// out: cursor for the item
+func (c *InputCursor) SelectItem(name string, index int) (*InputCursor, error) {
 
-	} else if !inputField.HasValue() {
-		return cursor, fmt.Errorf(
-			"no default value for input name '%s' could be determined",
-			name)
+	var (
+		err error
+		rg  *RepeatableGroup
+	)
+
+	if rg, err = c.currentRepeatableGroup(name); err != nil {
+		return c, err
 	}
+	if index < 0 || index >= len(rg.items) {
+		return c, fmt.Errorf(
+			"item %d of '%s' does not exist",
+			index, name)
+	}
+	c.history.record(cursorStep{Op: selectItemStep, Cursor: c.id, Name: name, Index: index})
+	return c.itemCursor(rg.items[index]), nil
+}
 
-	if len(currInput.Inputs()) > 0 {
+// in: name - of the repeatable group expected at the cursor
+// out: the repeatable group at the current cursor position
+func (c *InputCursor) currentRepeatableGroup(name string) (*RepeatableGroup, error) {
 
-		// input for which value was set has dependents.
-		// so update cursor to point to the dependents.
-		cursor = &InputCursor{
-			parents: append([]*InputCursor{c}, c.parents...),
-			group:   currInput,
-			index:   -1,
+	var (
+		ok    bool
+		input Input
+		rg    *RepeatableGroup
+	)
+
+	if c.index == -1 {
+		return nil, fmt.Errorf("cursor needs to be advanced before retrieving input")
+	}
+	input = c.group.Inputs()[c.index]
+	if input.Name() != name {
+		return nil, fmt.Errorf(
+			"cursor is at input '%s' which is different from provided input name '%s'",
+			input.Name(), name)
+	}
+	if rg, ok = input.(*RepeatableGroup); !ok {
+		return nil, fmt.Errorf("input '%s' is not a repeatable group", name)
+	}
+	return rg, nil
+}
+
+// in: item - input group of an item of a repeatable group
+// out: a cursor positioned before the item's first input
+func (c *InputCursor) itemCursor(item *InputGroup) *InputCursor {
+
+	return c.history.register(&InputCursor{
+		parents:   append([]*InputCursor{c}, c.parents...),
+		group:     item,
+		index:     -1,
+		repeating: true,
+
+		history: c.history,
+
+		tags: c.tags,
+	})
+}
+
+// The inputs visited by a cursor and the values
+// set on them which are used to navigate back to
+// previous inputs and to undo changes
+type cursorHistory struct {
+	// positions of the inputs visited in order
+	positions []cursorPosition
+	// stack of changes made to input values
+	changes []valueChange
+
+	// cursors derived from the first cursor
+	// in the order they were created and
+	// the steps taken with them which are
+	// replayed to restore a saved cursor
+	cursors []*InputCursor
+	steps   []cursorStep
+}
+
+// The position of a cursor which is the
+// cursor and the indexes of the cursor
+// and all of its parents
+type cursorPosition struct {
+	cursor  *InputCursor
+	indexes []int
+}
+
+// A change to the value of an input field
+type valueChange struct {
+	// position at which the value was set
+	position cursorPosition
+	// index of the position in the history
+	historyIndex int
+
+	field    *InputField
+	value    *string
+	source   ValueSource
+	inputSet bool
+}
+
+// moves the cursor back to the previous input. the
+// inputs that depend on the input moved back to are
+// cleared as their input may no longer be applicable.
+//
+// out: cursor at the previous input or nil if the
+//      cursor is at the first input
+func (c *InputCursor) PrevInput() *InputCursor {
+
+	// a cursor for the dependents of an input that
+	// has not been advanced is still at that input
+	last := len(c.history.positions) - 1
+	if c.index == -1 {
+		last++
+	}
+	if last < 1 {
+		return nil
+	}
+	c.history.record(cursorStep{Op: prevStep, Cursor: c.id})
+	return c.rewind(last - 1)
+}
+
+// moves the cursor back to the last position at which
+// the input with the given name was visited. the inputs
+// that depend on any of the inputs moved back past are
+// cleared as their input may no longer be applicable.
+//
+// in: name - of the input to move back to. this may be
+//            the name of a container or one of its inputs
+// out: cursor at the input
+func (c *InputCursor) Rewind(name string) (*InputCursor, error) {
+
+	for i := len(c.history.positions) - 1; i >= 0; i-- {
+
+		if c.history.positions[i].isAt(name) {
+			c.history.record(cursorStep{Op: rewindStep, Cursor: c.id, Name: name})
+			return c.rewind(i), nil
 		}
 	}
+	return c, fmt.Errorf(
+		"input '%s' has not been visited by the cursor",
+		name)
+}
+
+// restores the value of the input field changed last
+// and moves the cursor back to that field's position
+//
+// out: cursor at the input whose value was restored
+func (c *InputCursor) Undo() (*InputCursor, error) {
+
+	var (
+		err    error
+		cursor *InputCursor
+	)
+
+	h := c.history
+	if len(h.changes) == 0 {
+		return c, fmt.Errorf("there are no changes to undo")
+	}
+	change := h.changes[len(h.changes)-1]
+	h.changes = h.changes[:len(h.changes)-1]
+	h.record(cursorStep{Op: undoStep, Cursor: c.id})
+
+	if change.historyIndex >= 0 &&
+		change.historyIndex < len(h.positions) &&
+		h.positions[change.historyIndex].cursor == change.position.cursor {
+
+		cursor = c.rewind(change.historyIndex)
+	} else {
+		// the position has already been moved
+		// back past so it is visited again
+		h.positions = append(h.positions, change.position)
+		cursor = change.position.restore()
+	}
+
+	if err = change.field.restoreValue(change.value, change.source, change.inputSet); err != nil {
+		return cursor, err
+	}
 	return cursor, nil
 }
+
+// in: index - index of the position in the history to move to
+// out: cursor at the position
+func (c *InputCursor) rewind(index int) *InputCursor {
+
+	h := c.history
+	for i := len(h.positions) - 1; i >= index; i-- {
+		clearDependents(h.positions[i].input())
+	}
+	position := h.positions[index]
+	h.positions = h.positions[:index+1]
+	return position.restore()
+}
+
+// in: inputField - the field whose value is about to be set
+// out: the change to record once the value has been set
+func (c *InputCursor) newValueChange(inputField *InputField) (valueChange, error) {
+
+	var (
+		err error
+
+		value *string
+	)
+
+	change := valueChange{
+		position:     c.position(),
+		historyIndex: -1,
+
+		field: inputField,
+	}
+
+	inputField.igMx.RLock()
+	change.inputSet = inputField.inputSet
+	change.source = inputField.source
+	value = inputField.valueDeref()
+	if ref := inputField.secretRef(); ref != nil {
+		// the value is restored from the secret as the
+		// reference is removed from the store once the
+		// value is replaced
+		value, err = inputField.resolveSecret(*ref)
+	}
+	change.value = copyValue(value)
+	inputField.igMx.RUnlock()
+
+	if err != nil {
+		return change, err
+	}
+	for i := len(c.history.positions) - 1; i >= 0; i-- {
+		if p := c.history.positions[i]; p.cursor == c && p.indexes[0] == c.index {
+			change.historyIndex = i
+			break
+		}
+	}
+	return change, nil
+}
+
+// out: the current position of the cursor
+func (c *InputCursor) position() cursorPosition {
+
+	indexes := make([]int, 0, len(c.parents)+1)
+	indexes = append(indexes, c.index)
+	for _, p := range c.parents {
+		indexes = append(indexes, p.index)
+	}
+	return cursorPosition{
+		cursor:  c,
+		indexes: indexes,
+	}
+}
+
+// out: the cursor moved back to the position
+func (p cursorPosition) restore() *InputCursor {
+
+	p.cursor.index = p.indexes[0]
+	for i, parent := range p.cursor.parents {
+		parent.index = p.indexes[i+1]
+	}
+	return p.cursor
+}
+
+// out: the input at the position
+func (p cursorPosition) input() Input {
+	return p.cursor.group.Inputs()[p.indexes[0]]
+}
+
+// in: name - of an input
+// out: whether the position is at the input with the given
+//      name or at a container which includes that input
+func (p cursorPosition) isAt(name string) bool {
+
+	input := p.input()
+	if input.Name() == name {
+		return true
+	}
+	if input.Type() == Container {
+		for _, ii := range input.Inputs() {
+			if ii.Name() == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// in: input - input whose dependent inputs should be cleared
+func clearDependents(input Input) {
+
+	switch input.Type() {
+	case Container:
+		for _, i := range input.Inputs() {
+			clearInputs(i.Inputs())
+		}
+	case Repeatable:
+		// items of repeatable groups
+		// are added and removed explicitly
+	default:
+		clearInputs(input.Inputs())
+	}
+}
+
+// in: inputs - inputs whose values should be cleared
+func clearInputs(inputs []Input) {
+
+	for _, i := range inputs {
+		switch input := i.(type) {
+		case *InputField:
+			input.ClearInput()
+			clearInputs(input.Inputs())
+		case *InputGroup:
+			clearInputs(input.Inputs())
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package forms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mevansam/goutils/crypto"
+)
+
+// steps taken with a cursor
+const (
+	nextStep       = "next"
+	prevStep       = "prev"
+	rewindStep     = "rewind"
+	undoStep       = "undo"
+	setStep        = "set"
+	addItemStep    = "addItem"
+	selectItemStep = "selectItem"
+)
+
+// The saved state of a cursor which is the steps
+// taken with it and the cursors derived from it.
+// The steps include the values entered so
+// restoring the state also restores the values.
+// Values of sensitive fields are encrypted with a
+// key derived from a passphrase and the salt saved
+// with them.
+type cursorState struct {
+	Form        string       `json:"form"`
+	Fingerprint string       `json:"fingerprint"`
+	Tags        []string     `json:"tags,omitempty"`
+	Salt        []byte       `json:"salt,omitempty"`
+	Steps       []cursorStep `json:"steps"`
+	Cursor      int          `json:"cursor"`
+}
+
+// A step taken with a cursor
+type cursorStep struct {
+	Op        string       `json:"op"`
+	Cursor    int          `json:"cursor"`
+	Name      string       `json:"name,omitempty"`
+	Value     *string      `json:"value,omitempty"`
+	Source    *ValueSource `json:"source,omitempty"`
+	Encrypted string       `json:"encrypted,omitempty"`
+	Index     int          `json:"index,omitempty"`
+
+	// whether the value set is of a sensitive field
+	sensitive bool
+}
+
+// This error is returned when a cursor is restored
+// from a state saved against a form whose definition
+// has changed since the state was saved
+type FormChangedError struct {
+	// name of the form
+	Form string
+}
+
+func (e *FormChangedError) Error() string {
+	return fmt.Sprintf(
+		"the definition of form '%s' has changed since the cursor state was saved",
+		e.Form)
+}
+
+// saves the state of the cursor along with the values
+// entered so far so that it can be restored via
+// RestoreCursor
+//
+// in: passphrase - passphrase used to encrypt values of
+//                  sensitive fields. it is only required
+//                  if such values have been entered.
+// out: the serialized state of the cursor
+func (c *InputCursor) MarshalState(passphrase string) ([]byte, error) {
+
+	var (
+		err error
+
+		ok    bool
+		group *InputGroup
+		crypt *crypto.Crypt
+	)
+
+	root := c.history.cursors[0]
+	if group, ok = root.group.(*InputGroup); !ok {
+		return nil, fmt.Errorf("cursor is not for an input form")
+	}
+
+	state := cursorState{
+		Form:   group.name,
+		Tags:   root.tags,
+		Steps:  make([]cursorStep, len(c.history.steps)),
+		Cursor: c.id,
+	}
+	copy(state.Steps, c.history.steps)
+
+	for i, step := range state.Steps {
+		if !step.sensitive || step.Value == nil {
+			continue
+		}
+		if crypt == nil {
+			if len(passphrase) == 0 {
+				return nil, fmt.Errorf(
+					"a passphrase is required to save the value of sensitive field '%s'",
+					step.Name)
+			}
+			if state.Salt, err = newSalt(); err != nil {
+				return nil, err
+			}
+			if crypt, err = newPassphraseCrypt(passphrase, state.Salt); err != nil {
+				return nil, err
+			}
+		}
+		if state.Steps[i].Encrypted, err = crypt.EncryptB64(*step.Value); err != nil {
+			return nil, err
+		}
+		state.Steps[i].Value = nil
+	}
+
+	if state.Fingerprint, err = formFingerprint(group); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&state)
+}
+
+// rebuilds a cursor from its saved state by replaying
+// the steps taken with it against the given form
+//
+// in: data       - the state saved via InputCursor.MarshalState
+// in: input      - the form the state was saved against
+// in: passphrase - passphrase used to decrypt values of sensitive fields
+// out: the cursor at the position it was saved at. if
+//      the form has changed since the state was saved
+//      a FormChangedError is returned.
+func RestoreCursor(data []byte, input *InputGroup, passphrase string) (*InputCursor, error) {
+
+	var (
+		err error
+
+		state       cursorState
+		fingerprint string
+		cursor      *InputCursor
+		crypt       *crypto.Crypt
+	)
+
+	if err = json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	if state.Form != input.name {
+		return nil, fmt.Errorf(
+			"cursor state was saved for form '%s' and not '%s'",
+			state.Form, input.name)
+	}
+	if fingerprint, err = formFingerprint(input); err != nil {
+		return nil, err
+	}
+	if fingerprint != state.Fingerprint {
+		return nil, &FormChangedError{Form: input.name}
+	}
+
+	for i, step := range state.Steps {
+		if len(step.Encrypted) == 0 {
+			continue
+		}
+		if crypt == nil {
+			if len(passphrase) == 0 {
+				return nil, fmt.Errorf(
+					"a passphrase is required to restore the value of sensitive field '%s'",
+					step.Name)
+			}
+			if crypt, err = newPassphraseCrypt(passphrase, state.Salt); err != nil {
+				return nil, err
+			}
+		}
+		value, err := crypt.DecryptB64(step.Encrypted)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decrypt value of field '%s'", step.Name)
+		}
+		state.Steps[i].Value = &value
+	}
+
+	cursor = NewInputCursor(input, state.Tags...)
+	h := cursor.history
+
+	for i, step := range state.Steps {
+		if step.Cursor < 0 || step.Cursor >= len(h.cursors) {
+			return nil, fmt.Errorf(
+				"step %d of the cursor state refers to an unknown cursor",
+				i+1)
+		}
+		c := h.cursors[step.Cursor]
+
+		switch step.Op {
+		case nextStep:
+			c.NextInput()
+		case prevStep:
+			c.PrevInput()
+		case rewindStep:
+			_, err = c.Rewind(step.Name)
+		case undoStep:
+			_, err = c.Undo()
+		case setStep:
+			if step.Value != nil && step.Source != nil {
+				_, err = c.setSourcedInput(step.Name, *step.Value, *step.Source)
+			} else if step.Value != nil {
+				_, err = c.SetInput(step.Name, *step.Value)
+			} else {
+				_, err = c.SetDefaultInput(step.Name)
+			}
+		case addItemStep:
+			_, err = c.AddItem(step.Name)
+		case selectItemStep:
+			_, err = c.SelectItem(step.Name, step.Index)
+		default:
+			err = fmt.Errorf("unknown step '%s'", step.Op)
+		}
+		if err != nil {
+			return nil, fmt.Errorf(
+				"unable to replay step %d of the cursor state: %w",
+				i+1, err)
+		}
+	}
+
+	if state.Cursor < 0 || state.Cursor >= len(h.cursors) {
+		return nil, fmt.Errorf("cursor state refers to an unknown cursor")
+	}
+	return h.cursors[state.Cursor], nil
+}
+
+// in: c - cursor to add to the history
+// out: c identified by its position in the history
+func (h *cursorHistory) register(c *InputCursor) *InputCursor {
+	c.id = len(h.cursors)
+	h.cursors = append(h.cursors, c)
+	return c
+}
+
+// in: step - step taken with a cursor of the history
+func (h *cursorHistory) record(step cursorStep) {
+	h.steps = append(h.steps, step)
+}
+
+// in: g - the form to fingerprint
+// out: a hash of the form's definition
+func formFingerprint(g *InputGroup) (string, error) {
+
+	var (
+		err error
+
+		gd   *groupDocument
+		data []byte
+	)
+
+	if gd, err = newGroupDocument(g); err != nil {
+		return "", err
+	}
+	// hints can be added at any time and do
+	// not change the inputs a cursor visits
+	gd.clearHints()
+	if data, err = json.Marshal(gd); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// removes the hints of the fields of the group
+// and the groups of its repeatable groups
+func (gd *groupDocument) clearHints() {
+
+	for i := range gd.Fields {
+		gd.Fields[i].Hints = nil
+	}
+	for i := range gd.Repeatables {
+		gd.Repeatables[i].clearHints()
+	}
+}
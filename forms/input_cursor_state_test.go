@@ -0,0 +1,149 @@
+package forms_test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mevansam/goforms/forms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	test_data "github.com/mevansam/goforms/test/data"
+)
+
+var _ = Describe("Input Cursor State", func() {
+
+	var (
+		err error
+
+		cursor *forms.InputCursor
+		input  forms.Input
+		state  []byte
+	)
+
+	newForm := func() *forms.InputGroup {
+		ig := test_data.NewTestInputCollection().Group("input-form")
+		for _, f := range ig.InputFields() {
+			err = f.SetValueRef(new(string))
+			Expect(err).ToNot(HaveOccurred())
+		}
+		return ig
+	}
+
+	BeforeEach(func() {
+
+		cursor = forms.NewInputCursor(newForm(), "tag1")
+		cursor = cursor.NextInput()
+		cursor, err = cursor.SetInput("attrib12", "value for attrib12 - B")
+		Expect(err).ToNot(HaveOccurred())
+		cursor = cursor.NextInput()
+		cursor, err = cursor.SetInput("attrib122", "value for attrib122")
+		Expect(err).ToNot(HaveOccurred())
+		cursor = cursor.NextInput()
+
+		state, err = cursor.MarshalState("")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("restores a cursor with the values entered", func() {
+
+		ig := newForm()
+		cursor, err = forms.RestoreCursor(state, ig, "")
+		Expect(err).ToNot(HaveOccurred())
+
+		input, err = cursor.GetCurrentInput()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(input.Name()).To(Equal("attrib1221"))
+		Expect(ig.InputValues()).To(Equal(map[string]string{
+			"attrib12":  "value for attrib12 - B",
+			"attrib122": "value for attrib122",
+		}))
+
+		// the restored cursor continues where it left off
+		cursor, err = cursor.SetInput("attrib1221", "value for attrib1221")
+		Expect(err).ToNot(HaveOccurred())
+		cursor = cursor.NextInput()
+		input, err = cursor.GetCurrentInput()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(input.Name()).To(Equal("attrib131"))
+
+		// as does its history
+		cursor, err = cursor.Rewind("attrib12")
+		Expect(err).ToNot(HaveOccurred())
+		input, err = cursor.GetCurrentInput()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(input.Name()).To(Equal("group1"))
+	})
+
+	It("detects changes to the form definition", func() {
+
+		var (
+			changedErr *forms.FormChangedError
+		)
+
+		ig := newForm()
+		_, err = ig.NewInputField(forms.FieldAttributes{
+			Name:        "attrib15",
+			DisplayName: "Attrib 15",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = forms.RestoreCursor(state, ig, "")
+		Expect(errors.As(err, &changedErr)).To(BeTrue())
+		Expect(changedErr.Form).To(Equal("input-form"))
+
+		_, err = forms.RestoreCursor(state, test_data.NewTestInputCollection().Group("input-form2"), "")
+		Expect(err).To(HaveOccurred())
+	})
+	It("does not consider hints to be part of the form definition", func() {
+
+		ig := newForm()
+		Expect(ig.AddFieldValueHint("attrib131", "field://attrib12")).To(Succeed())
+
+		cursor, err = forms.RestoreCursor(state, ig, "")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("encrypts the values of sensitive fields", func() {
+
+		newSecretForm := func() *forms.InputGroup {
+			ig := forms.NewInputCollection().NewGroup("login", "login credentials")
+			for _, attributes := range []forms.FieldAttributes{
+				{Name: "user", DisplayName: "User"},
+				{Name: "password", DisplayName: "Password", Sensitive: true},
+			} {
+				_, err = ig.NewInputField(attributes)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			for _, f := range ig.InputFields() {
+				Expect(f.SetValueRef(new(string))).To(Succeed())
+			}
+			return ig
+		}
+
+		cursor = forms.NewInputCursor(newSecretForm())
+		cursor, err = cursor.NextInput().SetInput("user", "gopher")
+		Expect(err).ToNot(HaveOccurred())
+		cursor, err = cursor.NextInput().SetInput("password", "s3cr3t")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = cursor.MarshalState("")
+		Expect(err).To(HaveOccurred())
+		state, err = cursor.MarshalState("passphrase")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(strings.Contains(string(state), "s3cr3t")).To(BeFalse())
+		Expect(strings.Contains(string(state), "gopher")).To(BeTrue())
+
+		_, err = forms.RestoreCursor(state, newSecretForm(), "wrong")
+		Expect(err).To(HaveOccurred())
+
+		ig := newSecretForm()
+		_, err = forms.RestoreCursor(state, ig, "passphrase")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ig.InputValues()).To(Equal(map[string]string{
+			"user":     "gopher",
+			"password": "s3cr3t",
+		}))
+	})
+})
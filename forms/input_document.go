@@ -0,0 +1,402 @@
+package forms
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of a form definition document
+type DocumentFormat int
+
+const (
+	YAML DocumentFormat = iota
+	JSON
+)
+
+// input type names used in form definition documents
+var inputTypeNames = map[InputType]string{
+	String:       "string",
+	Number:       "number",
+	FilePath:     "filePath",
+	HttpUrl:      "httpUrl",
+	EmailAddress: "emailAddress",
+	JsonInput:    "json",
+}
+
+// Declarative definition of a collection of
+// input groups which can be serialized as
+// YAML or JSON.
+type collectionDocument struct {
+	Groups []groupDocument `yaml:"groups" json:"groups"`
+}
+
+type groupDocument struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+
+	Containers []containerDocument `yaml:"containers,omitempty" json:"containers,omitempty"`
+	Fields     []fieldDocument     `yaml:"fields,omitempty" json:"fields,omitempty"`
+
+	// repeatable groups are added to
+	// the group after all its fields
+	Repeatables []repeatableDocument `yaml:"repeatables,omitempty" json:"repeatables,omitempty"`
+}
+
+type repeatableDocument struct {
+	groupDocument `yaml:",inline"`
+
+	DisplayName string `yaml:"displayName,omitempty" json:"displayName,omitempty"`
+	MinItems    int    `yaml:"minItems,omitempty" json:"minItems,omitempty"`
+	MaxItems    int    `yaml:"maxItems,omitempty" json:"maxItems,omitempty"`
+}
+
+type containerDocument struct {
+	Name        string `yaml:"name" json:"name"`
+	DisplayName string `yaml:"displayName,omitempty" json:"displayName,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	GroupID     int    `yaml:"groupId" json:"groupId"`
+}
+
+type fieldDocument struct {
+	Name        string `yaml:"name" json:"name"`
+	DisplayName string `yaml:"displayName,omitempty" json:"displayName,omitempty"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+
+	GroupID   int    `yaml:"groupId,omitempty" json:"groupId,omitempty"`
+	InputType string `yaml:"inputType,omitempty" json:"inputType,omitempty"`
+
+	ValueFromFile bool    `yaml:"valueFromFile,omitempty" json:"valueFromFile,omitempty"`
+	DefaultValue  *string `yaml:"defaultValue,omitempty" json:"defaultValue,omitempty"`
+	Sensitive     bool    `yaml:"sensitive,omitempty" json:"sensitive,omitempty"`
+
+	ListInput bool `yaml:"listInput,omitempty" json:"listInput,omitempty"`
+	MinItems  int  `yaml:"minItems,omitempty" json:"minItems,omitempty"`
+	MaxItems  int  `yaml:"maxItems,omitempty" json:"maxItems,omitempty"`
+
+	EnvVars   []string `yaml:"envVars,omitempty" json:"envVars,omitempty"`
+	DependsOn []string `yaml:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	Tags      []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+
+	InclusionFilter             string `yaml:"inclusionFilter,omitempty" json:"inclusionFilter,omitempty"`
+	InclusionFilterErrorMessage string `yaml:"inclusionFilterErrorMessage,omitempty" json:"inclusionFilterErrorMessage,omitempty"`
+	ExclusionFilter             string `yaml:"exclusionFilter,omitempty" json:"exclusionFilter,omitempty"`
+	ExclusionFilterErrorMessage string `yaml:"exclusionFilterErrorMessage,omitempty" json:"exclusionFilterErrorMessage,omitempty"`
+
+	AcceptedValues             []string `yaml:"acceptedValues,omitempty" json:"acceptedValues,omitempty"`
+	AcceptedValuesErrorMessage string   `yaml:"acceptedValuesErrorMessage,omitempty" json:"acceptedValuesErrorMessage,omitempty"`
+
+	Hints []string `yaml:"hints,omitempty" json:"hints,omitempty"`
+}
+
+// in: reader - reader for a YAML or JSON form definition document
+// out: a new input collection with the groups defined in the document
+func LoadCollection(reader io.Reader) (*InputCollection, error) {
+
+	ic := NewInputCollection()
+	if err := ic.Load(reader); err != nil {
+		return nil, err
+	}
+	return ic, nil
+}
+
+// in: reader - reader for a YAML or JSON form definition document
+//              whose groups will be added to this collection
+func (ic *InputCollection) Load(reader io.Reader) error {
+
+	var (
+		err error
+
+		doc collectionDocument
+	)
+
+	// JSON documents are decoded as such so that
+	// errors are reported in terms of JSON syntax
+	br := bufio.NewReader(reader)
+	if isJSONDocument(br) {
+		decoder := json.NewDecoder(br)
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&doc)
+	} else {
+		decoder := yaml.NewDecoder(br)
+		decoder.KnownFields(true)
+		if err = decoder.Decode(&doc); err == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing form definition document: %s", err.Error())
+	}
+
+	for _, gd := range doc.Groups {
+		if len(gd.Name) == 0 {
+			return fmt.Errorf("form definition document has a group without a name")
+		}
+		if ic.HasGroup(gd.Name) {
+			return fmt.Errorf("a group with name '%s' has already been added", gd.Name)
+		}
+		if err = gd.build(ic.NewGroup(gd.Name, gd.Description)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// in: writer - writer to which a form definition document for all
+//              groups in this collection will be written
+// in: format - the format of the document to write
+func (ic *InputCollection) Export(writer io.Writer, format DocumentFormat) error {
+
+	var (
+		err error
+		doc collectionDocument
+		gd  *groupDocument
+	)
+
+	groups := ic.Groups()
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].name < groups[j].name
+	})
+
+	doc.Groups = make([]groupDocument, 0, len(groups))
+	for _, g := range groups {
+		if gd, err = newGroupDocument(g); err != nil {
+			return err
+		}
+		doc.Groups = append(doc.Groups, *gd)
+	}
+
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(&doc)
+	case YAML:
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+		if err = encoder.Encode(&doc); err == nil {
+			err = encoder.Close()
+		}
+	default:
+		err = fmt.Errorf("unknown form definition document format: %d", format)
+	}
+	return err
+}
+
+// in: reader - buffered reader of the document
+// out: whether the document is a JSON document
+func isJSONDocument(reader *bufio.Reader) bool {
+
+	for i := 1; ; i++ {
+		peek, _ := reader.Peek(i)
+		if len(peek) < i {
+			return false
+		}
+		switch c := peek[i-1]; c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		default:
+			return c == '{'
+		}
+	}
+}
+
+// in: g - the input group to add containers and fields to
+func (gd *groupDocument) build(g *InputGroup) error {
+
+	var (
+		err error
+
+		input     Input
+		inputType InputType
+
+		rg *RepeatableGroup
+	)
+
+	for _, cd := range gd.Containers {
+		if cd.GroupID <= 0 {
+			return fmt.Errorf(
+				"container '%s' of group '%s' must have a group id greater than 0",
+				cd.Name, gd.Name)
+		}
+		g.NewInputContainer(cd.Name, cd.DisplayName, cd.Description, cd.GroupID)
+	}
+	for _, fd := range gd.Fields {
+		if inputType, err = parseInputTypeName(fd.InputType); err != nil {
+			return fmt.Errorf(
+				"field '%s' of group '%s' has an invalid input type: %s",
+				fd.Name, gd.Name, err.Error())
+		}
+		if input, err = g.NewInputField(FieldAttributes{
+			Name:                        fd.Name,
+			DisplayName:                 fd.DisplayName,
+			Description:                 fd.Description,
+			GroupID:                     fd.GroupID,
+			InputType:                   inputType,
+			ValueFromFile:               fd.ValueFromFile,
+			DefaultValue:                fd.DefaultValue,
+			Sensitive:                   fd.Sensitive,
+			ListInput:                   fd.ListInput,
+			MinItems:                    fd.MinItems,
+			MaxItems:                    fd.MaxItems,
+			EnvVars:                     fd.EnvVars,
+			DependsOn:                   fd.DependsOn,
+			Tags:                        fd.Tags,
+			InclusionFilter:             fd.InclusionFilter,
+			InclusionFilterErrorMessage: fd.InclusionFilterErrorMessage,
+			ExclusionFilter:             fd.ExclusionFilter,
+			ExclusionFilterErrorMessage: fd.ExclusionFilterErrorMessage,
+			AcceptedValues:              fd.AcceptedValues,
+			AcceptedValuesErrorMessage:  fd.AcceptedValuesErrorMessage,
+		}); err != nil {
+			return err
+		}
+		for _, hint := range fd.Hints {
+			if err = g.AddFieldValueHint(input.Name(), hint); err != nil {
+				return fmt.Errorf(
+					"field '%s' of group '%s' has an invalid hint '%s': %s",
+					fd.Name, gd.Name, hint, err.Error())
+			}
+		}
+	}
+	for _, rd := range gd.Repeatables {
+		if len(rd.Name) == 0 {
+			return fmt.Errorf(
+				"group '%s' has a repeatable group without a name",
+				gd.Name)
+		}
+		if rg, err = g.NewRepeatableGroup(
+			rd.Name,
+			rd.DisplayName,
+			rd.Description,
+			rd.MinItems,
+			rd.MaxItems,
+		); err != nil {
+			return err
+		}
+		if err = rd.groupDocument.build(&rg.InputGroup); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// in: g - the input group to create a document for
+// out: the document describing the group
+func newGroupDocument(g *InputGroup) (*groupDocument, error) {
+
+	gd := &groupDocument{
+		Name:        g.name,
+		Description: g.description,
+	}
+
+	groupIds := make([]int, 0, len(g.containers))
+	for id := range g.containers {
+		groupIds = append(groupIds, id)
+	}
+	sort.Ints(groupIds)
+	for _, id := range groupIds {
+		c := g.containers[id]
+		gd.Containers = append(gd.Containers, containerDocument{
+			Name:        c.name,
+			DisplayName: c.displayName,
+			Description: c.description,
+			GroupID:     c.groupId,
+		})
+	}
+
+	// fields are written in the order they were
+	// added as the structure of the form depends
+	// on it and dependencies need to be added
+	// before the fields that depend on them
+	fields := g.InputFields()
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].order < fields[j].order
+	})
+	for _, f := range fields {
+		fd := fieldDocument{
+			Name:        f.name,
+			DisplayName: f.displayName,
+			Description: f.description,
+
+			GroupID: f.groupId,
+
+			ValueFromFile: f.valueFromFile,
+			DefaultValue:  f.defaultValue,
+			Sensitive:     f.sensitive,
+
+			ListInput: f.listInput,
+			MinItems:  f.minItems,
+			MaxItems:  f.maxItems,
+
+			EnvVars:   f.envVars,
+			DependsOn: f.dependsOn,
+			Tags:      f.tags,
+
+			AcceptedValues:             f.acceptedValues,
+			AcceptedValuesErrorMessage: f.acceptedValuesErrorMessage,
+
+			Hints: g.fieldValueLookupHints[f.name],
+		}
+		if name, exists := inputTypeNames[f.inputType]; exists {
+			fd.InputType = name
+		} else {
+			return nil, fmt.Errorf(
+				"field '%s' has an input type that cannot be exported: %d",
+				f.name, f.inputType)
+		}
+		if f.inclusionFilter != nil {
+			fd.InclusionFilter = f.inclusionFilter.String()
+			fd.InclusionFilterErrorMessage = f.inclusionFilterErrorMessage
+		}
+		if f.exclusionFilter != nil {
+			fd.ExclusionFilter = f.exclusionFilter.String()
+			fd.ExclusionFilterErrorMessage = f.exclusionFilterErrorMessage
+		}
+		gd.Fields = append(gd.Fields, fd)
+	}
+
+	repeatables := []*RepeatableGroup{}
+	for _, i := range g.fieldNameSet {
+		if rg, ok := i.(*RepeatableGroup); ok {
+			repeatables = append(repeatables, rg)
+		}
+	}
+	sort.Slice(repeatables, func(i, j int) bool {
+		return repeatables[i].order < repeatables[j].order
+	})
+	for _, rg := range repeatables {
+		rgd, err := newGroupDocument(&rg.InputGroup)
+		if err != nil {
+			return nil, err
+		}
+		gd.Repeatables = append(gd.Repeatables, repeatableDocument{
+			groupDocument: *rgd,
+
+			DisplayName: rg.displayName,
+			MinItems:    rg.minItems,
+			MaxItems:    rg.maxItems,
+		})
+	}
+	return gd, nil
+}
+
+// in: name - name of an input type in a form definition document
+// out: the input type with the given name. String if name is empty
+func parseInputTypeName(name string) (InputType, error) {
+
+	if len(name) == 0 {
+		return String, nil
+	}
+	for t, n := range inputTypeNames {
+		if strings.EqualFold(n, name) {
+			return t, nil
+		}
+	}
+	return String, fmt.Errorf("unknown input type '%s'", name)
+}
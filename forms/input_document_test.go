@@ -0,0 +1,143 @@
+package forms_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/mevansam/goforms/forms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	test_data "github.com/mevansam/goforms/test/data"
+)
+
+var _ = Describe("Input Documents", func() {
+
+	var (
+		err error
+		ic  *forms.InputCollection
+	)
+
+	BeforeEach(func() {
+		ic = test_data.NewTestInputCollection()
+	})
+
+	It("exports and reloads a collection as yaml", func() {
+
+		var (
+			exported, reexported bytes.Buffer
+		)
+
+		err = ic.Group("input-form").AddFieldValueHint("attrib133", "field://attrib132/attrib132")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ic.Export(&exported, forms.YAML)
+		Expect(err).NotTo(HaveOccurred())
+
+		loaded, err := forms.LoadCollection(bytes.NewReader(exported.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(loaded.Groups())).To(Equal(3))
+		Expect(loaded.Group("input-form").String()).To(Equal(ic.Group("input-form").String()))
+
+		err = loaded.Export(&reexported, forms.YAML)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reexported.String()).To(Equal(exported.String()))
+	})
+
+	It("exports and reloads a collection as json", func() {
+
+		var (
+			exported, reexported bytes.Buffer
+		)
+
+		err = ic.Export(&exported, forms.JSON)
+		Expect(err).NotTo(HaveOccurred())
+
+		loaded, err := forms.LoadCollection(bytes.NewReader(exported.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Group("input-form").String()).To(Equal(ic.Group("input-form").String()))
+
+		err = loaded.Export(&reexported, forms.JSON)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reexported.String()).To(Equal(exported.String()))
+	})
+
+	It("loads field attributes from a document", func() {
+
+		loaded, err := forms.LoadCollection(strings.NewReader(testFormDocument))
+		Expect(err).NotTo(HaveOccurred())
+
+		ig := loaded.Group("server")
+		Expect(ig).NotTo(BeNil())
+
+		field, err := ig.GetInputField("protocol")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.AcceptedValues()).To(Equal([]string{"http", "https"}))
+		Expect(*field.DefaultValue()).To(Equal("https"))
+
+		field, err = ig.GetInputField("port")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.Type()).To(Equal(forms.Number))
+		Expect(field.EnvVars()).To(Equal([]string{"SERVER_PORT"}))
+
+		err = field.SetValueRef(new(string))
+		Expect(err).NotTo(HaveOccurred())
+		port := "99999"
+		err = field.SetValue(&port)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("port must have 1 to 4 digits"))
+
+		err = ig.AddFieldValueHint("region", "file://regions")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns an error for invalid documents", func() {
+
+		_, err = forms.LoadCollection(strings.NewReader(`{"groups": [{"name": "g", "fields": [{"name": "f", "inputType": "date"}]}]}`))
+		Expect(err).To(HaveOccurred())
+
+		_, err = forms.LoadCollection(strings.NewReader("groups:\n  - name: g\n    unknown: true\n"))
+		Expect(err).To(HaveOccurred())
+
+		_, err = forms.LoadCollection(strings.NewReader("groups:\n  - name: g\n    fields:\n      - name: f\n        dependsOn: [missing]\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+const testFormDocument = `
+groups:
+  - name: server
+    description: server configuration
+    containers:
+      - name: endpoint
+        displayName: Endpoint
+        description: server endpoint
+        groupId: 1
+    fields:
+      - name: protocol
+        displayName: Protocol
+        description: the server protocol.
+        defaultValue: https
+        acceptedValues: [http, https]
+        acceptedValuesErrorMessage: protocol must be http or https
+      - name: port
+        displayName: Port
+        description: the server port.
+        inputType: number
+        envVars: [SERVER_PORT]
+        dependsOn: [protocol]
+        inclusionFilter: ^[0-9]{1,4}$
+        inclusionFilterErrorMessage: port must have 1 to 4 digits
+      - name: host
+        displayName: Host
+        groupId: 1
+      - name: address
+        displayName: Address
+        groupId: 1
+      - name: region
+        displayName: Region
+        tags: [cloud]
+        hints:
+          - https://example.com/regions
+`
@@ -1,6 +1,7 @@
 package forms
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -20,6 +21,10 @@ type InputField struct {
 
 	inputType InputType
 
+	// order in which the field was
+	// added to its input form
+	order int
+
 	valueFromFile bool
 	envVars       []string
 	defaultValue  *string
@@ -27,10 +32,28 @@ type InputField struct {
 	hasValue bool
 	inputSet bool
 
+	// where the bound value came from
+	source ValueSource
+
 	valueRef interface{}
+	// codec used to convert values if the
+	// bound value object is not a string
+	valueCodec *valueCodec
 
 	sensitive bool
 
+	// listeners subscribed to
+	// changes of the value
+	observers changeObservers
+
+	// whether the field collects a list
+	// of values and the bounds on the
+	// number of items in the list
+	listInput bool
+	minItems  int
+	maxItems  int
+
+	dependsOn           []string
 	postFieldConditions []postCondition
 	tags                []string
 
@@ -45,11 +68,6 @@ type InputField struct {
 	exclusionFilterErrorMessage string
 }
 
-type postCondition struct {
-	field  *InputField
-	values []string
-}
-
 // in: inclusionFilter - field value must match this regex
 // in: inclusionFilterErrorMessage - error message to return if inclusion filter does not match
 func (f *InputField) SetInclusionFilter(
@@ -128,7 +146,6 @@ func (f *InputField) Enabled(evaluate bool, tags ...string) bool {
 
 	var (
 		enabled bool
-		value   *string
 	)
 
 	if len(tags) > 0 {
@@ -144,17 +161,11 @@ func (f *InputField) Enabled(evaluate bool, tags ...string) bool {
 	} else {
 		enabled = true
 	}
-	if evaluate && enabled && len(f.postFieldConditions) > 0 {
+	if evaluate && enabled {
+		// all conditions that apply need to be met
 		for _, c := range f.postFieldConditions {
-			if value = c.field.Value(); value != nil {
-				hasValue := false
-				for _, v := range c.values {
-					if *value == v {
-						hasValue = true
-						break
-					}
-				}
-				enabled = hasValue
+			if c.applies() && !c.expr.eval() {
+				enabled = false
 				break
 			}
 		}
@@ -162,6 +173,17 @@ func (f *InputField) Enabled(evaluate bool, tags ...string) bool {
 	return enabled
 }
 
+// out: the conditions which need to be met
+//      for the field to be enabled
+func (f *InputField) Conditions() []string {
+
+	conditions := make([]string, 0, len(f.postFieldConditions))
+	for _, c := range f.postFieldConditions {
+		conditions = append(conditions, c.expr.String())
+	}
+	return conditions
+}
+
 // out: environment variables associated with this field
 func (f *InputField) EnvVars() []string {
 	if f.envVars == nil {
@@ -200,22 +222,17 @@ func (f *InputField) LongDescription() string {
 		buf strings.Builder
 	)
 
-	buf.WriteString(f.description)
+	buf.WriteString(f.Description())
 
 	if f.envVars != nil && len(f.envVars) > 0 {
-		buf.WriteString(" It will be sourced from the environment variable")
+		text := "It will be sourced from the environment variable %s if not provided."
 		if len(f.envVars) > 1 {
-			buf.WriteString("s")
+			text = "It will be sourced from the environment variables %s if not provided."
 		}
+		// the translation is not used as a format so
+		// that any other '%' in it is shown as is
 		buf.WriteString(" ")
-
-		for i, v := range f.envVars {
-			if i > 0 {
-				buf.WriteString(", ")
-			}
-			buf.WriteString(v)
-		}
-		buf.WriteString(" if not provided.")
+		buf.WriteString(strings.Replace(f.localization.message(text), "%s", strings.Join(f.envVars, ", "), 1))
 	}
 
 	return buf.String()
@@ -229,6 +246,9 @@ func (f *InputField) Type() InputType {
 // out: whether a value can be returned for this input
 func (f *InputField) HasValue() bool {
 
+	f.igMx.RLock()
+	defer f.igMx.RUnlock()
+
 	if f.hasValue {
 		return true
 
@@ -247,13 +267,31 @@ func (f *InputField) HasValue() bool {
 // in: valueRef - pointer to a value or a pointer to a pointer to
 //                a value. changing the contents of this pointer
 //                will modify the value reference and hence the
-//								contents of the field.
+//								contents of the field. the value can be a
+//                string, bool, int, uint, float, time.Duration,
+//                []string or a type implementing the interface
+//                encoding.TextUnmarshaler. defaults are only
+//                applied to nil pointers and slices as any other
+//                value is considered to be set even if it is zero.
+//                a sensitive field of a form with a secret store
+//                can only be bound to a string.
 func (f *InputField) SetValueRef(valueRef interface{}) error {
 
 	var (
+		err    error
+		exists bool
+
 		ptrValue, ptrToValue reflect.Value
+
+		codec *valueCodec
+		value reflect.Value
+
+		defaulted bool
 	)
 
+	f.igMx.Lock()
+	defer f.igMx.Unlock()
+
 	ptrValue = reflect.ValueOf(valueRef) // pointer to the pointer of the value object
 	if ptrValue.Kind() == reflect.Ptr {
 		ptrToValue = reflect.Indirect(ptrValue) // value object or pointer to the value object
@@ -263,13 +301,14 @@ func (f *InputField) SetValueRef(valueRef interface{}) error {
 			if !f.hasValue && f.defaultValue != nil {
 				ptrToValue.Set(reflect.ValueOf(*f.defaultValue))
 				f.hasValue = true
+				defaulted = true
 			}
 
 			logger.TraceMessage(
 				"Binding input field '%s': 0x%x",
 				f.name, ptrValue.Pointer())
 
-		} else if ptrToValue.Kind() == reflect.Ptr {
+		} else if ptrToValue.Kind() == reflect.Ptr && ptrToValue.Type().Elem().Kind() == reflect.String {
 
 			if reflect.Indirect(ptrToValue).Kind() == reflect.Invalid {
 
@@ -277,85 +316,265 @@ func (f *InputField) SetValueRef(valueRef interface{}) error {
 					value := *f.defaultValue
 					ptrToValue.Set(reflect.ValueOf(&value))
 					f.hasValue = true
+					defaulted = true
 				} else {
 					f.hasValue = false
 				}
 
-			} else if reflect.Indirect(ptrToValue).Kind() == reflect.String {
-				f.hasValue = true
 			} else {
+				f.hasValue = true
+			}
+
+			logger.TraceMessage(
+				"Binding input field '%s': 0x%x => 0x%x",
+				f.name, ptrValue.Pointer(), ptrToValue.Pointer())
+
+		} else if ptrToValue.Kind() == reflect.Ptr {
+
+			if codec, exists = valueCodecFor(ptrToValue.Type().Elem()); !exists {
 				return fmt.Errorf(
-					"the field '%s' value object being bound must be of type string or nil",
-					f.name)
+					"the field '%s' value object being bound is of unsupported type '%s'",
+					f.name, ptrToValue.Type().Elem())
+			}
+			if err = f.checkSecretBinding(codec); err != nil {
+				return err
+			}
+			if ptrToValue.IsNil() {
+
+				if f.defaultValue != nil {
+					if value, err = codec.parse(*f.defaultValue); err != nil {
+						return f.conversionError(*f.defaultValue, ptrToValue.Type().Elem(), err)
+					}
+					ptr := reflect.New(value.Type())
+					ptr.Elem().Set(value)
+					ptrToValue.Set(ptr)
+					f.hasValue = true
+					defaulted = true
+				} else {
+					f.hasValue = false
+				}
+
+			} else {
+				f.hasValue = true
 			}
 
 			logger.TraceMessage(
 				"Binding input field '%s': 0x%x => 0x%x",
 				f.name, ptrValue.Pointer(), ptrToValue.Pointer())
 
+		} else if codec, exists = valueCodecFor(ptrToValue.Type()); exists {
+
+			if err = f.checkSecretBinding(codec); err != nil {
+				return err
+			}
+
+			// only a nil slice has no value as a zero
+			// value of any other type cannot be told
+			// apart from one that was explicitly set
+			f.hasValue = ptrToValue.Kind() != reflect.Slice || !ptrToValue.IsNil()
+			if !f.hasValue && f.defaultValue != nil {
+				if value, err = codec.parse(*f.defaultValue); err != nil {
+					return f.conversionError(*f.defaultValue, ptrToValue.Type(), err)
+				}
+				ptrToValue.Set(value)
+				f.hasValue = true
+				defaulted = true
+			}
+
+			logger.TraceMessage(
+				"Binding input field '%s': 0x%x",
+				f.name, ptrValue.Pointer())
+
 		} else {
 			return fmt.Errorf(
-				"the field '%s' value reference must be a pointer to string or a pointer to pointer to a string",
+				"the field '%s' value reference must be a pointer or a pointer to pointer to a value of a supported type",
 				f.name)
 		}
 
 	} else {
 		return fmt.Errorf(
-			"the field '%s' value reference must be a pointer to string or a pointer to pointer to a string",
+			"the field '%s' value reference must be a pointer or a pointer to pointer to a value of a supported type",
 			f.name)
 	}
 
 	f.valueRef = valueRef
+	f.valueCodec = codec
+
+	switch {
+	case defaulted:
+		f.source = ValueSource{Type: DefaultSource}
+	case f.hasValue:
+		f.source = ValueSource{Type: BoundSource}
+	default:
+		f.source = ValueSource{}
+	}
 	return nil
 }
 
+// How a value being set is bound to a field
+type bindMode int
+
+const (
+	// a value entered as input
+	bindInput bindMode = iota
+	// a path to a file the value is read from
+	bindFromFile
+	// a saved value which may be a reference
+	// to a value in the form's secret store
+	bindSaved
+	// a value chosen from the field's hints
+	bindHint
+)
+
 // in: value - input value to set
 func (f *InputField) SetValue(value *string) error {
+	if f.valueFromFile {
+		return f.setValue(value, bindFromFile)
+	}
+	return f.setValue(value, bindInput)
+}
+
+// sets the value of the field as it is given. unlike
+// SetValue the value of a field whose value is read
+// from a file is the content and not a path to read.
+//
+// in: value - input value to set
+func (f *InputField) SetContent(value *string) error {
+	return f.setValue(value, bindInput)
+}
+
+// in: value - input value to set
+// in: mode  - how the value should be bound
+func (f *InputField) setValue(value *string, mode bindMode) error {
 
 	var (
 		err error
 
-		buf  []byte
-		data string
+		path string
+	)
 
-		ptrValue, ptrToValue reflect.Value
+	if mode == bindFromFile && value != nil {
+		if value, path, err = f.readValueFile(*value); err != nil {
+			return err
+		}
+	}
+	return f.setSourcedValue(value, mode, sourceOf(value, path, mode))
+}
+
+// in: value  - input value to set
+// in: mode   - how the value should be bound
+// in: source - where the value came from
+func (f *InputField) setSourcedValue(value *string, mode bindMode, source ValueSource) error {
+
+	var (
+		err error
+
+		secret *storedSecret
+
+		boundRef,
+		oldValue, newValue *string
 	)
 
-	if f.valueRef == nil {
-		return fmt.Errorf("field '%s' has not been bound to a value instance", f.name)
+	notify := f.hasChangeListeners()
+
+	// values kept in the secret store are stored
+	// before the form is locked so that the form
+	// is not locked while the store is accessed
+	if secret, err = f.storeSecret(value, mode); err != nil {
+		return err
 	}
 
-	if f.valueFromFile {
-		// extract value from file
-		if buf, err = os.ReadFile(*value); err != nil {
-			return err
+	f.igMx.Lock()
+	if secret == nil {
+		if notify {
+			oldValue = copyValue(f.value())
+		}
+		if err = f.bindValue(value, source, true); err == nil && notify {
+			newValue = copyValue(f.value())
 		}
 
-		logger.TraceMessage(
-			"Value of input field '%s' has been sourced from file '%s'.",
-			f.name, *value)
+	} else if f.secrets.store != secret.store {
+		err = fmt.Errorf(
+			"the secret store of the form changed while the value of field '%s' was being set",
+			f.name)
 
-		data = string(buf)
-		value = &data
-	}
-	if f.acceptedValueSet != nil {
-		if _, ok := f.acceptedValueSet[*value]; !ok {
-			return fmt.Errorf(f.acceptedValuesErrorMessage)
+	} else {
+		if boundRef = copyValue(f.secretRef()); notify {
+			oldValue = secret.previousValue(f, boundRef)
 		}
+		if err = f.bindValue(secret.ref, source, false); err == nil && notify {
+			newValue = copyValue(secret.value)
+		}
+	}
+	if err == nil && notify {
+		f.queueChange(oldValue, newValue)
 	}
-	if f.inclusionFilter != nil && !f.inclusionFilter.MatchString(*value) {
-		return fmt.Errorf(f.inclusionFilterErrorMessage)
+	f.igMx.Unlock()
+
+	if secret != nil {
+		secret.release(f, boundRef, err)
 	}
-	if f.exclusionFilter != nil && f.exclusionFilter.MatchString(*value) {
-		return fmt.Errorf(f.exclusionFilterErrorMessage)
+	if notify {
+		// listeners are notified once the lock
+		// is released so they can access the form
+		f.notifyChanges()
+	}
+	return err
+}
+
+// in: path - path of the file to read the value from
+// out: the content of the file
+// out: the path of the file
+func (f *InputField) readValueFile(path string) (*string, string, error) {
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	logger.TraceMessage(
+		"Value of input field '%s' has been sourced from file '%s'.",
+		f.name, path)
+
+	data := string(buf)
+	return &data, path, nil
+}
+
+// in: value    - value to bind
+// in: source   - where the value came from
+// in: validate - whether the value needs to be validated
+func (f *InputField) bindValue(value *string, source ValueSource, validate bool) error {
+
+	var (
+		err error
+
+		ptrValue, ptrToValue reflect.Value
+	)
+
+	if f.valueRef == nil {
+		return fmt.Errorf("field '%s' has not been bound to a value instance", f.name)
+	}
+	if value != nil && validate {
+		if value, err = f.validated(*value); err != nil {
+			return err
+		}
 	}
 
 	ptrValue = reflect.ValueOf(f.valueRef)  // pointer to the pointer of the value object
 	ptrToValue = reflect.Indirect(ptrValue) // value object or pointer to the value object
 
-	// Update pointer for bound field
-	// to the new value pointer
-	if ptrToValue.Kind() == reflect.Ptr {
+	if f.valueCodec != nil {
+		if err = f.setTypedValue(ptrToValue, value); err != nil {
+			return err
+		}
+
+		logger.TraceMessage(
+			"Input field '%s' bound to typed object at 0x%x has been updated.",
+			f.name, ptrValue.Pointer())
+
+	} else if ptrToValue.Kind() == reflect.Ptr {
+		// Update pointer for bound field
+		// to the new value pointer
 		ptrToValue.Set(reflect.ValueOf(value))
 
 		logger.TraceMessage(
@@ -375,42 +594,106 @@ func (f *InputField) SetValue(value *string) error {
 	}
 
 	f.hasValue = (value != nil)
+	f.source = source
 	return nil
 }
 
+// in: value - a value of the field
+// out: the value if it is valid. list values
+//      are returned in their canonical form.
+func (f *InputField) validated(value string) (*string, error) {
+
+	if f.listInput {
+		// list values are validated item by item
+		return f.validateList(value)
+	}
+	if err := f.validateValue(value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
 // flags field as having its input set
 func (f *InputField) SetInput() {
+	f.igMx.Lock()
+	defer f.igMx.Unlock()
 	f.inputSet = true
 }
 
+// resets the field to its default value and
+// flags the field as not having its input set
+func (f *InputField) ClearInput() {
+
+	f.igMx.RLock()
+	bound := f.valueRef != nil
+	f.igMx.RUnlock()
+
+	if bound {
+		source := ValueSource{}
+		if f.defaultValue != nil {
+			source = ValueSource{Type: DefaultSource}
+		}
+		if err := f.setSourcedValue(f.defaultValue, bindInput, source); err != nil {
+			logger.TraceMessage(
+				"Unable to reset input field '%s' to its default value: %s",
+				f.name, err.Error())
+		}
+	}
+
+	f.igMx.Lock()
+	defer f.igMx.Unlock()
+	f.inputSet = false
+}
+
+// restores the value of the field, where it came
+// from and whether its input was set to what they
+// were before the field was changed
+//
+// in: value    - the value to restore
+// in: source   - where the value came from
+// in: inputSet - whether the field had its input set
+func (f *InputField) restoreValue(value *string, source ValueSource, inputSet bool) error {
+
+	f.igMx.RLock()
+	bound := f.valueRef != nil
+	f.igMx.RUnlock()
+
+	if bound {
+		if err := f.setSourcedValue(value, bindInput, source); err != nil {
+			return err
+		}
+	}
+
+	f.igMx.Lock()
+	defer f.igMx.Unlock()
+	f.inputSet = inputSet
+	return nil
+}
+
 // out: whether input has been set
 func (f *InputField) InputSet() bool {
+	f.igMx.RLock()
+	defer f.igMx.RUnlock()
 	return f.inputSet
 }
 
 // out: the value of the input
 func (f *InputField) Value() *string {
 
-	var (
-		value *string
-	)
+	f.igMx.RLock()
+	defer f.igMx.RUnlock()
 
-	value = f.valueDeref()
-	if value == nil && !f.valueFromFile {
-		if f.envVars != nil && len(f.envVars) > 0 {
-			// extract value from environment
-			for _, e := range f.envVars {
-				if envVal, exists := os.LookupEnv(e); exists {
+	// the value is copied as it may refer to a bound
+	// value object which may change once unlocked
+	return copyValue(f.value())
+}
 
-					logger.TraceMessage(
-						"Value of input field '%s' has been sourced from the environment variable '%s'.",
-						f.name, e)
+// out: the value of the input
+func (f *InputField) value() *string {
 
-					value = &envVal
-					break
-				}
-			}
-		}
+	value, _, err := f.sourcedValue()
+	if err != nil {
+		logger.ErrorMessage(err.Error())
 	}
 	return value
 }
@@ -426,7 +709,17 @@ func (f *InputField) valueDeref() *string {
 
 		ptrValue = reflect.ValueOf(f.valueRef)  // pointer to the pointer of the value object
 		ptrToValue = reflect.Indirect(ptrValue) // value object or pointer to the value object
-		if ptrToValue.Kind() == reflect.Ptr {
+		if f.valueCodec != nil {
+			if ptrToValue.Kind() == reflect.Ptr {
+				if ptrToValue.IsNil() {
+					return nil
+				}
+				ptrToValue = ptrToValue.Elem()
+			}
+			value := f.valueCodec.format(ptrToValue)
+			return &value
+
+		} else if ptrToValue.Kind() == reflect.Ptr {
 			return ptrToValue.Interface().(*string)
 		} else {
 			return ptrValue.Interface().(*string)
@@ -434,3 +727,88 @@ func (f *InputField) valueDeref() *string {
 	}
 	return nil
 }
+
+// in: value - a value of the field or an item of a list field
+// out: an error if the value is not valid for the field
+func (f *InputField) validateValue(value string) error {
+
+	var (
+		err error
+	)
+
+	// a file path type is validated by reading
+	// the file when the value is from a file
+	if !(f.valueFromFile && f.inputType == FilePath) {
+		if err = f.inputType.Validate(value); err != nil {
+			return &InputTypeError{
+				Field: f.name,
+				Type:  f.inputType,
+				Err:   err,
+			}
+		}
+	}
+	if f.acceptedValueSet != nil {
+		if _, ok := f.acceptedValueSet[value]; !ok {
+			return errors.New(f.localization.input(f.name, func(t Translation) string {
+				return t.AcceptedValuesErrorMessage
+			}, f.acceptedValuesErrorMessage))
+		}
+	}
+	if f.inclusionFilter != nil && !f.inclusionFilter.MatchString(value) {
+		return errors.New(f.localization.input(f.name, func(t Translation) string {
+			return t.InclusionFilterErrorMessage
+		}, f.inclusionFilterErrorMessage))
+	}
+	if f.exclusionFilter != nil && f.exclusionFilter.MatchString(value) {
+		return errors.New(f.localization.input(f.name, func(t Translation) string {
+			return t.ExclusionFilterErrorMessage
+		}, f.exclusionFilterErrorMessage))
+	}
+	return nil
+}
+
+// in: ptrToValue - the bound value object or pointer to the value object
+// in: value      - the value to convert and set. if nil the bound
+//                  value is set to its zero value or nil pointer
+func (f *InputField) setTypedValue(ptrToValue reflect.Value, value *string) error {
+
+	var (
+		err error
+
+		t            reflect.Type
+		typedValue   reflect.Value
+		isPtrToValue bool
+	)
+
+	t = ptrToValue.Type()
+	if isPtrToValue = ptrToValue.Kind() == reflect.Ptr; isPtrToValue {
+		t = t.Elem()
+	}
+
+	if value == nil {
+		ptrToValue.Set(reflect.Zero(ptrToValue.Type()))
+		return nil
+	}
+	if typedValue, err = f.valueCodec.parse(*value); err != nil {
+		return f.conversionError(*value, t, err)
+	}
+	if isPtrToValue {
+		ptr := reflect.New(t)
+		ptr.Elem().Set(typedValue)
+		ptrToValue.Set(ptr)
+	} else {
+		ptrToValue.Set(typedValue)
+	}
+	return nil
+}
+
+// out: error returned when a value cannot be
+//      converted to the type of the bound object
+func (f *InputField) conversionError(value string, t reflect.Type, err error) error {
+	if f.sensitive {
+		value = "****"
+	}
+	return fmt.Errorf(
+		"the value '%s' of field '%s' cannot be converted to type '%s': %s",
+		value, f.name, t, err.Error())
+}
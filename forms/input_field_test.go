@@ -1,6 +1,7 @@
 package forms_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 
@@ -153,6 +154,14 @@ var _ = Describe("Input Fields", func() {
 			value = "gophergophergopher"
 			err = field.SetValue(&value)
 			Expect(err).ToNot(HaveOccurred())
+
+			err = field.SetInclusionFilter("^[0-9]+$", "must be 100% numeric")
+			Expect(err).ToNot(HaveOccurred())
+
+			value = "gopher"
+			err = field.SetValue(&value)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("must be 100% numeric"))
 		})
 
 		It("validates field values using an exclusion filter", func() {
@@ -178,4 +187,70 @@ var _ = Describe("Input Fields", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	Context("input type validation", func() {
+
+		var (
+			typedGroup *forms.InputGroup
+		)
+
+		BeforeEach(func() {
+
+			typedGroup = ic.Group("input-form2")
+
+			for name, inputType := range map[string]forms.InputType{
+				"port":    forms.Number,
+				"path":    forms.FilePath,
+				"url":     forms.HttpUrl,
+				"email":   forms.EmailAddress,
+				"payload": forms.JsonInput,
+			} {
+				field, err := typedGroup.NewInputField(forms.FieldAttributes{
+					Name:        name,
+					DisplayName: name,
+					Description: "description for " + name + ".",
+					InputType:   inputType,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				err = field.(*forms.InputField).SetValueRef(new(string))
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("rejects values that do not match the field's input type", func() {
+
+			var (
+				typeErr *forms.InputTypeError
+			)
+
+			invalidValues := map[string]string{
+				"port":    "abc",
+				"path":    "/does/not/exist/file.txt",
+				"url":     "ftp://example.com",
+				"email":   "John Doe <john@example.com>",
+				"payload": `{"a": 1`,
+			}
+			for name, value := range invalidValues {
+				err = typedGroup.SetFieldValue(name, value)
+				Expect(err).To(HaveOccurred())
+				Expect(errors.As(err, &typeErr)).To(BeTrue())
+				Expect(typeErr.Field).To(Equal(name))
+			}
+		})
+
+		It("accepts values that match the field's input type", func() {
+
+			validValues := map[string]string{
+				"port":    "8080",
+				"path":    filepath.Join(workingDirectory, "new_file.txt"),
+				"url":     "https://example.com/path?q=1",
+				"email":   "john@example.com",
+				"payload": `{"a": [1, 2, 3]}`,
+			}
+			for name, value := range validValues {
+				err = typedGroup.SetFieldValue(name, value)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	})
 })
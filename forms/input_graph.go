@@ -0,0 +1,353 @@
+package forms
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// kinds of nodes in a form's input flow
+type flowNodeKind int
+
+const (
+	formNode flowNodeKind = iota
+	fieldNode
+	choiceNode
+	repeatableNode
+)
+
+// A node in the input flow of a form
+type flowNode struct {
+	id    string
+	label string
+	kind  flowNodeKind
+	tags  []string
+}
+
+// An edge in the input flow of a form. edges
+// between consecutive inputs of a group are
+// flagged as next edges and edges to dependent
+// inputs are labelled with their conditions.
+type flowEdge struct {
+	from, to string
+	label    string
+	next     bool
+}
+
+// The input flow of a form. the inputs of
+// repeatable groups are added to sub-graphs.
+type flowGraph struct {
+	id    string
+	label string
+
+	nodes     []flowNode
+	edges     []flowEdge
+	subgraphs []*flowGraph
+}
+
+// in: writer - writer to which the form's input flow
+//              will be written as a Graphviz DOT graph
+func (g *InputGroup) WriteDOT(writer io.Writer) error {
+
+	var (
+		err error
+
+		writeGraph func(fg *flowGraph, indent string) error
+	)
+
+	fg := g.flowGraph()
+
+	writeGraph = func(fg *flowGraph, indent string) error {
+		for _, n := range fg.nodes {
+			label := n.label
+			if len(n.tags) > 0 {
+				label += "\n[" + strings.Join(n.tags, ", ") + "]"
+			}
+			if _, err = fmt.Fprintf(writer,
+				"%s%s [label=%s, shape=%s];\n",
+				indent, dotQuote(n.id), dotQuote(label), dotShapes[n.kind],
+			); err != nil {
+				return err
+			}
+		}
+		for _, sg := range fg.subgraphs {
+			if _, err = fmt.Fprintf(writer,
+				"%ssubgraph %s {\n%s  label=%s;\n%s  style=dashed;\n",
+				indent, dotQuote("cluster_"+sg.id), indent, dotQuote(sg.label), indent,
+			); err != nil {
+				return err
+			}
+			if err = writeGraph(sg, indent+"  "); err != nil {
+				return err
+			}
+			if _, err = fmt.Fprintf(writer, "%s}\n", indent); err != nil {
+				return err
+			}
+		}
+		for _, e := range fg.edges {
+			attributes := []string{}
+			if e.next {
+				attributes = append(attributes, "style=dashed")
+			}
+			if len(e.label) > 0 {
+				attributes = append(attributes, "label="+dotQuote(e.label))
+			}
+			edge := indent + dotQuote(e.from) + " -> " + dotQuote(e.to)
+			if len(attributes) > 0 {
+				edge += " [" + strings.Join(attributes, ", ") + "]"
+			}
+			if _, err = fmt.Fprintln(writer, edge+";"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if _, err = fmt.Fprintf(writer, "digraph %s {\n  rankdir=TB;\n", dotQuote(g.name)); err != nil {
+		return err
+	}
+	if err = writeGraph(fg, "  "); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(writer, "}")
+	return err
+}
+
+// in: writer - writer to which the form's input flow
+//              will be written as a Mermaid flowchart
+func (g *InputGroup) WriteMermaid(writer io.Writer) error {
+
+	var (
+		err error
+
+		writeGraph func(fg *flowGraph, indent string) error
+	)
+
+	fg := g.flowGraph()
+
+	writeGraph = func(fg *flowGraph, indent string) error {
+		for _, n := range fg.nodes {
+			label := mermaidEscape(n.label)
+			if len(n.tags) > 0 {
+				label += "<br/>[" + mermaidEscape(strings.Join(n.tags, ", ")) + "]"
+			}
+			shape := mermaidShapes[n.kind]
+			if _, err = fmt.Fprintf(writer,
+				"%s%s%s\"%s\"%s\n",
+				indent, n.id, shape[0], label, shape[1],
+			); err != nil {
+				return err
+			}
+		}
+		for _, sg := range fg.subgraphs {
+			if _, err = fmt.Fprintf(writer,
+				"%ssubgraph %s [\"%s\"]\n",
+				indent, sg.id, mermaidEscape(sg.label),
+			); err != nil {
+				return err
+			}
+			if err = writeGraph(sg, indent+"  "); err != nil {
+				return err
+			}
+			if _, err = fmt.Fprintf(writer, "%send\n", indent); err != nil {
+				return err
+			}
+		}
+		for _, e := range fg.edges {
+			arrow := " --> "
+			if e.next {
+				arrow = " -.-> "
+			}
+			if len(e.label) > 0 {
+				arrow = strings.TrimSuffix(arrow, " ") + "|\"" + mermaidEscape(e.label) + "\"| "
+			}
+			if _, err = fmt.Fprintln(writer, indent+e.from+arrow+e.to); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if _, err = fmt.Fprintln(writer, "flowchart TD"); err != nil {
+		return err
+	}
+	return writeGraph(fg, "  ")
+}
+
+var dotShapes = map[flowNodeKind]string{
+	formNode:       "oval",
+	fieldNode:      "box",
+	choiceNode:     "diamond",
+	repeatableNode: "box3d",
+}
+
+var mermaidShapes = map[flowNodeKind][2]string{
+	formNode:       {"([", "])"},
+	fieldNode:      {"[", "]"},
+	choiceNode:     {"{", "}"},
+	repeatableNode: {"[[", "]]"},
+}
+
+// out: the input flow of the form
+func (g *InputGroup) flowGraph() *flowGraph {
+
+	fg := &flowGraph{id: "form"}
+	fg.nodes = append(fg.nodes, flowNode{
+		id:    "form",
+		label: g.name,
+		kind:  formNode,
+	})
+	g.addFlow(fg, "", "form", make(map[Input]bool))
+	return fg
+}
+
+// adds the group's inputs to the graph as a sequence
+// of inputs which starts at the given entry node
+//
+// in: fg      - the graph to add the inputs to
+// in: prefix  - prefix of the ids of the nodes added
+// in: entry   - the node the sequence of inputs starts at
+// in: visited - inputs already added
+func (g *InputGroup) addFlow(
+	fg *flowGraph,
+	prefix, entry string,
+	visited map[Input]bool,
+) {
+
+	var (
+		addInput func(input Input, parent *InputField)
+	)
+
+	addInput = func(input Input, parent *InputField) {
+
+		id := flowInputId(prefix, input)
+		if visited[input] {
+			return
+		}
+		visited[input] = true
+
+		switch i := input.(type) {
+
+		case *RepeatableGroup:
+			label := i.displayName
+			if i.maxItems > 0 {
+				label += fmt.Sprintf(" (%d..%d items)", i.minItems, i.maxItems)
+			} else {
+				label += fmt.Sprintf(" (%d.. items)", i.minItems)
+			}
+			fg.nodes = append(fg.nodes, flowNode{id: id, label: label, kind: repeatableNode})
+
+			// the inputs of each item follow the
+			// repeatable group in a sub-graph
+			sg := &flowGraph{
+				id:    id + "_items",
+				label: i.displayName + " items",
+			}
+			i.InputGroup.addFlow(sg, id+"_", id, make(map[Input]bool))
+			fg.subgraphs = append(fg.subgraphs, sg)
+
+		case *InputField:
+			label := i.displayName
+			if len(label) == 0 {
+				label = i.name
+			}
+			fg.nodes = append(fg.nodes, flowNode{id: id, label: label, kind: fieldNode, tags: i.tags})
+			for _, d := range i.inputs {
+				fg.edges = append(fg.edges, flowEdge{
+					from:  id,
+					to:    flowInputId(prefix, d),
+					label: conditionLabel(d, i),
+				})
+				addInput(d, i)
+			}
+
+		default:
+			// a container is a choice
+			// between its inputs
+			label := input.DisplayName()
+			if len(label) == 0 {
+				label = input.Name()
+			}
+			fg.nodes = append(fg.nodes, flowNode{id: id, label: label, kind: choiceNode})
+			for _, m := range input.Inputs() {
+				fg.edges = append(fg.edges, flowEdge{
+					from:  id,
+					to:    flowInputId(prefix, m),
+					label: conditionLabel(m, parent),
+				})
+				addInput(m, parent)
+			}
+		}
+	}
+
+	prev := entry
+	for _, i := range g.inputs {
+		id := flowInputId(prefix, i)
+		fg.edges = append(fg.edges, flowEdge{from: prev, to: id, next: prev != entry})
+		addInput(i, nil)
+		prev = id
+	}
+}
+
+// in: input  - a dependent input
+// in: parent - the field the input depends on
+// out: the conditions of the input that refer to the parent
+func conditionLabel(input Input, parent *InputField) string {
+
+	f, ok := input.(*InputField)
+	if !ok || parent == nil {
+		return ""
+	}
+	conditions := []string{}
+	for _, c := range f.postFieldConditions {
+		for _, cf := range c.fields {
+			if cf == parent {
+				conditions = append(conditions, termString(c.expr))
+				break
+			}
+		}
+	}
+	return strings.Join(conditions, " and ")
+}
+
+// in: prefix - prefix of the id
+// in: input  - an input of the form
+// out: the id of the input's node
+func flowInputId(prefix string, input Input) string {
+
+	switch input.Type() {
+	case Container:
+		return prefix + "c_" + flowId(input.Name())
+	case Repeatable:
+		return prefix + "r_" + flowId(input.Name())
+	}
+	return prefix + "f_" + flowId(input.Name())
+}
+
+var flowIdInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// in: name - name of an input
+// out: the name with characters that are not
+//      valid in a node id replaced
+func flowId(name string) string {
+	return flowIdInvalidChars.ReplaceAllString(name, "_")
+}
+
+// in: s - a string
+// out: the string as a quoted DOT id
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
+
+// in: s - a string
+// out: the string escaped for a quoted Mermaid label
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+	).Replace(s)
+}
@@ -0,0 +1,106 @@
+package forms_test
+
+import (
+	"bytes"
+
+	"github.com/mevansam/goforms/forms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	test_data "github.com/mevansam/goforms/test/data"
+)
+
+var _ = Describe("Input Form Flow Graphs", func() {
+
+	var (
+		err error
+		out bytes.Buffer
+		ig  *forms.InputGroup
+	)
+
+	BeforeEach(func() {
+
+		out.Reset()
+
+		ig = forms.NewInputCollection().NewGroup("cluster", "cluster settings")
+		ig.NewInputContainer("auth", "Authentication", "authentication method", 1)
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "token", DisplayName: "Token", GroupID: 1},
+			{Name: "password", DisplayName: "Password", GroupID: 1, Tags: []string{"basic", "legacy"}},
+			{Name: "provider", DisplayName: "Provider"},
+			{Name: "project", DisplayName: "Project", DependsOn: []string{`provider == "gcp"`}},
+		} {
+			_, err = ig.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		rg, err := ig.NewRepeatableGroup("nodes", "Node", "cluster nodes", 1, 3)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rg.NewInputField(forms.FieldAttributes{Name: "instance_type", DisplayName: "Instance Type"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("writes a mermaid flowchart", func() {
+
+		err = ig.WriteMermaid(&out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal(`flowchart TD
+  form(["cluster"])
+  c_auth{"Authentication"}
+  f_token["Token"]
+  f_password["Password<br/>[basic, legacy]"]
+  f_provider["Provider"]
+  f_project["Project"]
+  r_nodes[["Node (1..3 items)"]]
+  subgraph r_nodes_items ["Node items"]
+    r_nodes_f_instance_type["Instance Type"]
+    r_nodes --> r_nodes_f_instance_type
+  end
+  form --> c_auth
+  c_auth --> f_token
+  c_auth --> f_password
+  c_auth -.-> f_provider
+  f_provider -->|"provider == #quot;gcp#quot;"| f_project
+  f_provider -.-> r_nodes
+`))
+	})
+
+	It("writes a graphviz dot graph", func() {
+
+		err = ig.WriteDOT(&out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal(`digraph "cluster" {
+  rankdir=TB;
+  "form" [label="cluster", shape=oval];
+  "c_auth" [label="Authentication", shape=diamond];
+  "f_token" [label="Token", shape=box];
+  "f_password" [label="Password\n[basic, legacy]", shape=box];
+  "f_provider" [label="Provider", shape=box];
+  "f_project" [label="Project", shape=box];
+  "r_nodes" [label="Node (1..3 items)", shape=box3d];
+  subgraph "cluster_r_nodes_items" {
+    label="Node items";
+    style=dashed;
+    "r_nodes_f_instance_type" [label="Instance Type", shape=box];
+    "r_nodes" -> "r_nodes_f_instance_type";
+  }
+  "form" -> "c_auth";
+  "c_auth" -> "f_token";
+  "c_auth" -> "f_password";
+  "c_auth" -> "f_provider" [style=dashed];
+  "f_provider" -> "f_project" [label="provider == \"gcp\""];
+  "f_provider" -> "r_nodes" [style=dashed];
+}
+`))
+	})
+
+	It("labels the edges to dependent inputs with their conditions", func() {
+
+		err = test_data.NewTestInputCollection().Group("input-form").WriteDOT(&out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(ContainSubstring(
+			`  "c_group2" -> "f_attrib122" [label="attrib12 in (\"value for attrib12\", \"value for attrib12 - B\")"];` + "\n"))
+		Expect(out.String()).To(ContainSubstring(`  "f_attrib13" -> "f_attrib131";` + "\n"))
+		Expect(out.String()).To(ContainSubstring(`  "c_group1" -> "f_attrib14" [style=dashed];` + "\n"))
+	})
+})
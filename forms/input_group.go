@@ -2,10 +2,12 @@ package forms
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/mevansam/goutils/utils"
 )
@@ -21,6 +23,7 @@ const (
 	EmailAddress
 	JsonInput
 	Container
+	Repeatable
 )
 
 // Input abstraction
@@ -37,6 +40,7 @@ type Input interface {
 	EnabledInputs(evaluate bool, tags ...string) []Input
 
 	getGroupId() int
+	inputList() []Input
 }
 
 // InputForm abstraction
@@ -91,6 +95,17 @@ type FieldAttributes struct {
 	// indicates if the field value should be masked
 	Sensitive bool
 
+	// if true then the field collects a list
+	// of values. the value of the field is
+	// a json array of the items in the list
+	// and each item is validated separately.
+	ListInput bool
+	// the minimum and maximum number of items
+	// in a list. a maximum of 0 indicates
+	// that the number of items is not limited
+	MinItems,
+	MaxItems int
+
 	// any environment variables the value for
 	// this input can be sourced from
 	EnvVars []string
@@ -109,6 +124,20 @@ type FieldAttributes struct {
 	//   only if the given dependent field has
 	//   the given value
 	//
+	// - an expression such as:
+	//   `provider in ("aws", "gcp") and count >= 2`
+	//   which may compare field values using
+	//   =, !=, <, <=, >, >=, in, not in and the
+	//   regex operators =~ and !~, and combine
+	//   comparisons using and, or, not and
+	//   parenthesis. string values must be
+	//   quoted. a field name on its own is
+	//   satisfied when the field has a value.
+	//
+	// all conditions need to be satisfied once
+	// the fields they refer to have values. the
+	// field is input after the last field each
+	// condition refers to.
 	DependsOn []string
 
 	// tags used to create field subsets
@@ -149,10 +178,30 @@ type InputGroup struct {
 	fieldNameSet map[string]Input
 
 	fieldValueLookupHints map[string][]string
+
+	// form level validation rules
+	validators []FormValidator
+
+	// translations of the form's texts
+	localization *localization
+	// store of the values of sensitive fields
+	secrets *secrets
+
+	// guards the inputs and values of the
+	// form which are shared by its groups
+	igMx *sync.RWMutex
+	// number of the item if the group
+	// is an item of a repeatable group
+	itemNumber int
 }
 
-// Regex used to validate hints
-var hintRegex = regexp.MustCompile(`^(https?:\/\/|file:\/\/\/?|field:\/\/)([a-z0-9]+([\-\.]{1}[a-z0-9]+)*(\.[a-z]{2,5})*(:[0-9]{1,5})?)(\/.*)?$`)
+// Regex used to validate hints. its groups are the protocol of
+// an http(s) hint, the protocol and path of a file hint and the
+// protocol, field name and path of a field hint.
+var hintRegex = regexp.MustCompile(
+	`^(?:(https?):\/\/[a-zA-Z0-9]+(?:[\-\.][a-zA-Z0-9]+)*(?::[0-9]{1,5})?(?:\/\S*)?` +
+		`|(file):\/\/(\S.*)` +
+		`|(field):\/\/([a-zA-Z0-9_\-\.]+)(\/.*)?)$`)
 
 // in: name        - name of the container
 // in: displayName - the name to display when requesting input
@@ -174,9 +223,15 @@ func (g *InputGroup) NewInputContainer(
 		containers:   g.containers,
 		fieldNameSet: g.fieldNameSet,
 
+		localization: g.localization,
+		secrets:      g.secrets,
+		igMx:         g.igMx,
+
 		fieldValueLookupHints: g.fieldValueLookupHints,
 	}
+	g.igMx.Lock()
 	g.containers[groupId] = container
+	g.igMx.Unlock()
 
 	return container
 }
@@ -229,6 +284,14 @@ func (g *InputGroup) NewInputField(
 			attributes.AcceptedValuesErrorMessage,
 		)
 	}
+	if attributes.ListInput {
+		if err = field.SetListInput(
+			attributes.MinItems,
+			attributes.MaxItems,
+		); err != nil {
+			return nil, err
+		}
+	}
 
 	return field, nil
 }
@@ -279,6 +342,9 @@ func (g *InputGroup) newInputField(
 		field *InputField
 	)
 
+	g.igMx.Lock()
+	defer g.igMx.Unlock()
+
 	// Do not allow adding duplicate fields
 	if _, exists = g.fieldNameSet[name]; exists {
 		return nil, fmt.Errorf(
@@ -298,8 +364,13 @@ func (g *InputGroup) newInputField(
 			fieldNameSet: g.fieldNameSet,
 
 			fieldValueLookupHints: g.fieldValueLookupHints,
+
+			localization: g.localization,
+			secrets:      g.secrets,
+			igMx:         g.igMx,
 		},
 		inputType: inputType,
+		order:     len(g.fieldNameSet),
 
 		valueFromFile: valueFromFile,
 		envVars:       envVars,
@@ -310,6 +381,7 @@ func (g *InputGroup) newInputField(
 		valueRef: nil,
 
 		tags:                []string{},
+		dependsOn:           dependsOn,
 		postFieldConditions: []postCondition{},
 
 		acceptedValues:  nil,
@@ -330,43 +402,36 @@ func (g *InputGroup) newInputField(
 		var (
 			addToDepends func(
 				input Input,
-				names map[string]string,
+				names map[string]bool,
 			) (bool, error)
 
-			names map[string]string
+			names map[string]bool
 			added bool
+
+			expr   conditionExpr
+			fields []*InputField
 		)
 
 		addToDepends = func(
 			input Input,
-			names map[string]string,
+			names map[string]bool,
 		) (bool, error) {
-			for _, i := range input.Inputs() {
+			for _, i := range input.inputList() {
 
-				if value, exists := names[i.Name()]; exists && i.Type() != Container {
+				if names[i.Name()] && i.Type() != Container && i.Type() != Repeatable {
 					f := i.(*InputField)
 					if err = f.addInputField(field); err != nil {
 						return false, err
 					}
-					if len(value) > 0 {
-						// add post condition for field which will
-						// be skipped unless dependent field equals
-						// the given value
-						field.postFieldConditions = append(
-							field.postFieldConditions,
-							postCondition{
-								field:  f,
-								values: strings.Split(value, "|"),
-							},
-						)
-					}
 
 					delete(names, i.Name())
 					if len(names) == 0 {
 						return true, nil
 					}
 
-				} else if len(i.Inputs()) > 0 {
+				} else if i.Type() != Repeatable && len(i.inputList()) > 0 {
+					// inputs of repeatable groups are templates
+					// for items and cannot be depended on
 					if added, err = addToDepends(i, names); added || err != nil {
 						return added, err
 					}
@@ -375,19 +440,37 @@ func (g *InputGroup) newInputField(
 			return false, nil
 		}
 
-		names = make(map[string]string)
+		lookup := func(name string) (*InputField, error) {
+			if f, ok := g.fieldNameSet[name].(*InputField); ok && f != field {
+				return f, nil
+			}
+			return nil, fmt.Errorf(
+				"unable to add field '%s' as one or more dependent fields %v not found",
+				field.name, dependsOn)
+		}
+
+		names = make(map[string]bool)
 		for _, n := range dependsOn {
-			tuple := strings.Split(n, "=")
-			if len(tuple) == 1 {
-				names[tuple[0]] = ""
-			} else if len(tuple) > 2 {
+			if expr, fields, err = parseCondition(n, lookup); err != nil {
 				return nil,
 					fmt.Errorf(
-						"field '%s' has a depends that does not comfirm to format 'name[=value]': %v",
-						field.name, tuple)
-			} else {
-				names[tuple[0]] = tuple[1]
+						"field '%s' has an invalid depends on condition '%s': %w",
+						field.name, n, err)
 			}
+			if expr != nil {
+				// add post condition for field which will
+				// be skipped unless the condition is met
+				field.postFieldConditions = append(
+					field.postFieldConditions,
+					postCondition{
+						expr:   expr,
+						fields: fields,
+					},
+				)
+			}
+			// the field follows the last field added
+			// to the form that the condition refers to
+			names[fields[len(fields)-1].name] = true
 		}
 		if added, err = addToDepends(g, names); !added && err == nil {
 			err = fmt.Errorf(
@@ -477,7 +560,9 @@ func (g *InputGroup) String() string {
 			}
 			out.WriteString(input.Name())
 			
-			if input.Type() != Container {
+			if input.Type() == Repeatable {
+				out.WriteString(fmt.Sprintf(" : items[%d]", len(input.(*RepeatableGroup).items)))
+			} else if input.Type() != Container {
 				if inputField, err = g.GetInputField(input.Name()); err == nil {
 					if val = inputField.Value(); val != nil {
 						out.WriteString(" = ")
@@ -488,9 +573,7 @@ func (g *InputGroup) String() string {
 						if j > 0 {
 							out.WriteString(", ")
 						}
-						out.WriteString(c.field.Name())
-						out.WriteRune('=')
-						out.WriteString(fmt.Sprintf("%+q", c.values))
+						out.WriteString(c.expr.String())
 					}
 					out.WriteRune(']')
 					out.WriteString(fmt.Sprintf("; tags%+q", inputField.tags))
@@ -520,17 +603,27 @@ func (g *InputGroup) Name() string {
 
 // out: the display name of the group
 func (g *InputGroup) DisplayName() string {
-	return g.displayName
+
+	displayName := g.localization.input(g.name, func(t Translation) string {
+		return t.DisplayName
+	}, g.displayName)
+
+	if g.itemNumber > 0 {
+		return fmt.Sprintf("%s #%d", displayName, g.itemNumber)
+	}
+	return displayName
 }
 
 // out: the description of the group
 func (g *InputGroup) Description() string {
-	return g.description
+	return g.localization.input(g.name, func(t Translation) string {
+		return t.Description
+	}, g.description)
 }
 
 // out: the long description of the group
 func (g *InputGroup) LongDescription() string {
-	return g.description
+	return g.Description()
 }
 
 // out: returns input type of "Container"
@@ -540,7 +633,13 @@ func (g *InputGroup) Type() InputType {
 
 // out: a list of all inputs for the group
 func (g *InputGroup) Inputs() []Input {
-	return g.inputs
+
+	g.igMx.RLock()
+	defer g.igMx.RUnlock()
+
+	// inputs may be added to the group
+	// once the lock has been released
+	return append([]Input{}, g.inputs...)
 }
 
 // in: evaluate - whether field's dependencies should be evaluated
@@ -556,8 +655,12 @@ func (g *InputGroup) Enabled(evaluate bool, tags ...string) bool {
 //      tags and satisfies the input's post-condition
 func (g *InputGroup) EnabledInputs(evaluate bool, tags ...string) []Input {
 
-	inputs := make([]Input, 0, len(g.inputs))
-	for _, i := range g.inputs {
+	// the inputs are evaluated without holding the
+	// lock as their conditions read field values
+	all := g.Inputs()
+
+	inputs := make([]Input, 0, len(all))
+	for _, i := range all {
 		if i.Enabled(evaluate, tags...) {
 			inputs = append(inputs, i)
 		}
@@ -570,6 +673,12 @@ func (g *InputGroup) getGroupId() int {
 	return g.groupId
 }
 
+// out: the inputs of the group. it must be
+//      called while holding the form's lock.
+func (g *InputGroup) inputList() []Input {
+	return g.inputs
+}
+
 // interface: InputForm
 
 // in: binds the given target data structure to this input form's fields
@@ -594,6 +703,12 @@ func (g *InputGroup) BindFields(target interface{}) error {
 
 		if name, ok = f.Tag.Lookup("form_field"); ok {
 
+			if rg, isRepeatable := g.fieldNameSet[name].(*RepeatableGroup); isRepeatable {
+				if err = rg.SetValueRef(v.Elem().Field(i).Addr().Interface()); err != nil {
+					return err
+				}
+				continue
+			}
 			if field, err = g.GetInputField(name); err != nil {
 				return err
 			}
@@ -625,6 +740,10 @@ func (g *InputGroup) AddFieldValueHint(name, hint string) error {
 	if !hintRegex.Match([]byte(hint)) {
 		return fmt.Errorf("hint must be a url with prefix http(s)://, file:// or field://")
 	}
+
+	g.igMx.Lock()
+	defer g.igMx.Unlock()
+
 	if _, exists = g.fieldNameSet[name]; exists {
 		if hints, exists = g.fieldValueLookupHints[name]; !exists {
 			hints = []string{}
@@ -640,6 +759,47 @@ func (g *InputGroup) AddFieldValueHint(name, hint string) error {
 // out: array of hint values
 func (g *InputGroup) GetFieldValueHints(name string) ([]string, error) {
 
+	var (
+		err  error
+		errs []error
+
+		values []string
+	)
+
+	g.igMx.RLock()
+	hints := append([]string{}, g.fieldValueLookupHints[name]...)
+	g.igMx.RUnlock()
+
+	hintValues := []string{}
+	for _, hint := range hints {
+		match := hintRegex.FindStringSubmatch(hint)
+
+		switch {
+		case len(match[1]) > 0:
+			values, err = getHttpHintValues(hint)
+		case len(match[2]) > 0:
+			// file:///path is an absolute path
+			// and file://path is a relative path
+			values, err = getFileHintValues(match[3])
+		case len(match[4]) > 0:
+			values, err = g.getFieldHintValues(match[5], match[6])
+		}
+		if err != nil {
+			// values of the other hints are still
+			// returned along with all the errors
+			errs = append(errs, err)
+		} else {
+			hintValues = append(hintValues, values...)
+		}
+	}
+	return hintValues, errors.Join(errs...)
+}
+
+// in: fieldName - name of a field with json content
+// in: fieldPath - path to the hint values in the field's content
+// out: hint values at the path
+func (g *InputGroup) getFieldHintValues(fieldName, fieldPath string) ([]string, error) {
+
 	var (
 		err   error
 		value *string
@@ -649,56 +809,44 @@ func (g *InputGroup) GetFieldValueHints(name string) ([]string, error) {
 	)
 
 	hintValues := []string{}
-	if hints := g.fieldValueLookupHints[name]; len(hints) > 0 {
-
-		for _, hint := range hints {
-			matchIndices := hintRegex.FindAllStringSubmatchIndex(hint, -1)
-			protocol := hint[matchIndices[0][2]:matchIndices[0][3]]
-
-			switch protocol {
-			case "http//", "https//":
-				// not implemented
-				err = fmt.Errorf("not implemented")
-			case "file://", "file:///":
-				// not implemented
-				err = fmt.Errorf("not implemented")
-			case "field://":
-
-				fieldName := hint[matchIndices[0][4]:matchIndices[0][5]]
-				fieldPath := hint[matchIndices[0][12]:matchIndices[0][13]]
-
-				if value, err = g.GetFieldValue(fieldName); err == nil && value != nil {
-
-					if err = json.Unmarshal([]byte(*value), &fieldData); err == nil {
-						if hintData, err = utils.GetValueAtPath(fieldPath, fieldData); err == nil {
-
-							switch data := hintData.(type) {
-							case string:
-								hintValues = append(hintValues, hintData.(string))
-							case []interface{}:
-								for _, v := range data {
-									hintValues = append(hintValues, fmt.Sprintf("%v", v))
-								}
-							default:
-								hintValues = append(hintValues, fmt.Sprintf("%v", hintData))
-							}
-						}
-					} else {
-						err = fmt.Errorf(
-							"error parsing json value of field '%s': %s",
-							fieldName, err.Error())
-					}
-				}
-			}
+	if value, err = g.GetFieldValue(fieldName); err != nil || value == nil {
+		return hintValues, err
+	}
+	if err = json.Unmarshal([]byte(*value), &fieldData); err != nil {
+		return nil, fmt.Errorf(
+			"error parsing json value of field '%s': %s",
+			fieldName, err.Error())
+	}
+	if hintData, err = utils.GetValueAtPath(fieldPath, fieldData); err != nil {
+		return nil, err
+	}
+
+	switch data := hintData.(type) {
+	case string:
+		hintValues = append(hintValues, data)
+	case []interface{}:
+		for _, v := range data {
+			hintValues = append(hintValues, fmt.Sprintf("%v", v))
 		}
+	default:
+		hintValues = append(hintValues, fmt.Sprintf("%v", hintData))
 	}
-	return hintValues, err
+	return hintValues, nil
 }
 
 // in: the name of the input field to retrieve
 // out: the input field with the given name
 func (g *InputGroup) GetInputField(name string) (*InputField, error) {
 
+	g.igMx.RLock()
+	defer g.igMx.RUnlock()
+	return g.getInputField(name)
+}
+
+// in: the name of the input field to retrieve
+// out: the input field with the given name
+func (g *InputGroup) getInputField(name string) (*InputField, error) {
+
 	var (
 		input Input
 		field *InputField
@@ -716,17 +864,24 @@ func (g *InputGroup) GetInputField(name string) (*InputField, error) {
 
 // in: the name of the input field whose value should be retrieved
 // out: a reference to the value of the input field
+// out: an error if the field does not exist or its value
+//      could not be retrieved from the form's secret store
 func (g *InputGroup) GetFieldValue(name string) (*string, error) {
 
 	var (
 		err   error
 		field *InputField
+		value *string
 	)
 
-	if field, err = g.GetInputField(name); err != nil {
+	g.igMx.RLock()
+	defer g.igMx.RUnlock()
+
+	if field, err = g.getInputField(name); err != nil {
 		return nil, err
 	}
-	return field.Value(), nil
+	value, _, err = field.sourcedValue()
+	return copyValue(value), err
 }
 
 // in: the name of the input field to set the value of
@@ -746,6 +901,9 @@ func (g *InputGroup) SetFieldValue(name string, value string) error {
 
 // out: a list of all fields for the group
 func (g *InputGroup) InputFields() []*InputField {
+
+	g.igMx.RLock()
+	defer g.igMx.RUnlock()
 	return g.inputFields(make(map[string]bool))
 }
 
@@ -760,6 +918,11 @@ func (g *InputGroup) inputFields(added map[string]bool) []*InputField {
 			ig := f.(*InputGroup)
 			fields = append(fields, ig.inputFields(added)...)
 
+		} else if f.Type() == Repeatable {
+			// fields of repeatable groups
+			// belong to the group's items
+			continue
+
 		} else if _, exists := added[f.Name()]; !exists {
 			fields = append(fields, f.(*InputField))
 			added[f.Name()] = true
@@ -779,14 +942,64 @@ func (g *InputGroup) InputValues() map[string]string {
 		val *string
 	)
 
+	g.igMx.RLock()
+	defer g.igMx.RUnlock()
+
 	valueMap := make(map[string]string)
-	inputFields := g.InputFields()
+	inputFields := g.inputFields(make(map[string]bool))
 
 	for _, f := range inputFields {
-		if f.InputSet() {
-			val = f.Value()
+		if f.inputSet {
+			val = f.inputValue()
 			valueMap[f.Name()] = *val
 		}
 	}
+	// the items of repeatable groups are
+	// returned as a json array of objects
+	for name, i := range g.fieldNameSet {
+		if rg, ok := i.(*RepeatableGroup); ok && len(rg.items) > 0 {
+			data, _ := json.Marshal(rg.itemValues())
+			valueMap[name] = string(data)
+		}
+	}
+	return valueMap
+}
+
+// out: map of name-values of all inputs entered where the
+//      items of list fields are lists of strings and the
+//      items of repeatable groups are lists of such maps
+func (g *InputGroup) InputValueMap() map[string]interface{} {
+
+	g.igMx.RLock()
+	defer g.igMx.RUnlock()
+
+	return g.inputValueMap()
+}
+
+// out: map of name-values of all inputs entered where the
+//      items of list fields are lists of strings and the
+//      items of repeatable groups are lists of such maps
+func (g *InputGroup) inputValueMap() map[string]interface{} {
+
+	valueMap := make(map[string]interface{})
+	for _, f := range g.inputFields(make(map[string]bool)) {
+		if f.inputSet {
+			value := f.inputValue()
+			if f.listInput && f.secretRef() == nil {
+				// values of list fields are saved
+				// in their canonical json form
+				if items, err := parseListValue(*value); err == nil {
+					valueMap[f.Name()] = items
+					continue
+				}
+			}
+			valueMap[f.Name()] = *value
+		}
+	}
+	for name, i := range g.fieldNameSet {
+		if rg, ok := i.(*RepeatableGroup); ok && len(rg.items) > 0 {
+			valueMap[name] = rg.itemValues()
+		}
+	}
 	return valueMap
 }
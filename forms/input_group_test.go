@@ -1,7 +1,15 @@
 package forms_test
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
 	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goutils/utils"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -222,4 +230,250 @@ var _ = Describe("Input Groups", func() {
 			Expect(*value).To(Equal("attrib122 #1"))
 		})
 	})
+
+	Context("typed value binding", func() {
+
+		It("binds fields to typed values of a data structure", func() {
+
+			var (
+				value *string
+			)
+
+			g := forms.NewInputCollection().NewGroup("typed", "typed fields")
+			for _, attributes := range []forms.FieldAttributes{
+				{Name: "port", InputType: forms.Number, DefaultValue: utils.PtrToStr("8080")},
+				{Name: "enabled", DefaultValue: utils.PtrToStr("true")},
+				{Name: "retries", InputType: forms.Number},
+				{Name: "ratio", InputType: forms.Number},
+				{Name: "timeout"},
+				{Name: "servers"},
+				{Name: "address"},
+			} {
+				_, err = g.NewInputField(attributes)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			data := struct {
+				Port    *int          `form_field:"port"`
+				Enabled bool          `form_field:"enabled"`
+				Retries *uint8        `form_field:"retries"`
+				Ratio   float64       `form_field:"ratio"`
+				Timeout time.Duration `form_field:"timeout"`
+				Servers []string      `form_field:"servers"`
+				Address net.IP        `form_field:"address"`
+			}{
+				Timeout: time.Minute,
+			}
+
+			err = g.BindFields(&data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*data.Port).To(Equal(8080))
+			// an explicit zero value is not replaced by the default
+			Expect(data.Enabled).To(BeFalse())
+
+			value, err = g.GetFieldValue("port")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*value).To(Equal("8080"))
+			value, err = g.GetFieldValue("timeout")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*value).To(Equal("1m0s"))
+			value, err = g.GetFieldValue("enabled")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*value).To(Equal("false"))
+			value, err = g.GetFieldValue("retries")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(value).To(BeNil())
+
+			Expect(g.SetFieldValue("port", "443")).To(Succeed())
+			Expect(g.SetFieldValue("enabled", "true")).To(Succeed())
+			Expect(g.SetFieldValue("retries", "3")).To(Succeed())
+			Expect(g.SetFieldValue("ratio", "0.75")).To(Succeed())
+			Expect(g.SetFieldValue("timeout", "90s")).To(Succeed())
+			Expect(g.SetFieldValue("servers", "10.0.0.1, 10.0.0.2")).To(Succeed())
+			Expect(g.SetFieldValue("address", "192.168.1.1")).To(Succeed())
+
+			Expect(*data.Port).To(Equal(443))
+			Expect(data.Enabled).To(BeTrue())
+			Expect(*data.Retries).To(Equal(uint8(3)))
+			Expect(data.Ratio).To(Equal(0.75))
+			Expect(data.Timeout).To(Equal(90 * time.Second))
+			Expect(data.Servers).To(Equal([]string{"10.0.0.1", "10.0.0.2"}))
+			Expect(data.Address.Equal(net.ParseIP("192.168.1.1"))).To(BeTrue())
+
+			// value updates in struct should reflect
+			// when retrieved via the form
+			data.Ratio = 1.5
+			value, err = g.GetFieldValue("ratio")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*value).To(Equal("1.5"))
+			value, err = g.GetFieldValue("address")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*value).To(Equal("192.168.1.1"))
+			value, err = g.GetFieldValue("servers")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*value).To(Equal(`["10.0.0.1","10.0.0.2"]`))
+
+			Expect(g.SetFieldValue("enabled", "maybe")).NotTo(Succeed())
+			Expect(g.SetFieldValue("retries", "300")).NotTo(Succeed())
+			Expect(*data.Retries).To(Equal(uint8(3)))
+		})
+
+		It("does not bind values of unsupported types", func() {
+
+			field, err := ig.GetInputField("attrib11")
+			Expect(err).NotTo(HaveOccurred())
+
+			err = field.SetValueRef(&map[string]string{})
+			Expect(err).To(HaveOccurred())
+			value := struct{ A int }{}
+			err = field.SetValueRef(&value)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("form validation", func() {
+
+		BeforeEach(func() {
+			for _, f := range ig.InputFields() {
+				err = f.SetValueRef(new(string))
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			ig.AddValidator(func(form forms.InputForm) map[string]error {
+				v1, _ := form.GetFieldValue("attrib1311")
+				v2, _ := form.GetFieldValue("attrib1312")
+				if v1 != nil && v2 != nil && *v1 == *v2 {
+					return map[string]error{
+						"attrib1312": fmt.Errorf("must differ from attrib1311"),
+					}
+				}
+				return nil
+			})
+			ig.AddValidator(func(form forms.InputForm) map[string]error {
+				v, _ := form.GetFieldValue("attrib14")
+				if v == nil || *v != "x" {
+					return map[string]error{
+						"attrib11": fmt.Errorf("attrib11 requires attrib14 to be 'x'"),
+						"attrib14": fmt.Errorf("must be 'x'"),
+					}
+				}
+				return nil
+			})
+		})
+
+		It("returns all violations of the form's validation rules", func() {
+
+			var (
+				validationErr *forms.FormValidationError
+			)
+
+			Expect(ig.SetFieldValue("attrib1311", "a")).To(Succeed())
+			Expect(ig.SetFieldValue("attrib1312", "a")).To(Succeed())
+
+			err = ig.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(errors.As(err, &validationErr)).To(BeTrue())
+			Expect(validationErr.Fields()).To(Equal([]string{"attrib11", "attrib1312", "attrib14"}))
+			Expect(err.Error()).To(Equal(
+				"form validation failed: " +
+					"field 'attrib11': attrib11 requires attrib14 to be 'x'; " +
+					"field 'attrib1312': must differ from attrib1311; " +
+					"field 'attrib14': must be 'x'",
+			))
+
+			Expect(ig.SetFieldValue("attrib1312", "b")).To(Succeed())
+			Expect(ig.SetFieldValue("attrib14", "x")).To(Succeed())
+			Expect(ig.Validate()).To(Succeed())
+		})
+
+		It("is asserted on forms that support validation", func() {
+
+			var form forms.InputForm = ig
+			validatingForm, ok := form.(forms.ValidatingForm)
+			Expect(ok).To(BeTrue())
+			Expect(validatingForm.Validate()).To(HaveOccurred())
+		})
+	})
+
+	Context("field value hints", func() {
+
+		BeforeEach(func() {
+			for _, f := range ig.InputFields() {
+				err = f.SetValueRef(new(string))
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+
+		It("retrieves hint values from a file", func() {
+
+			err = ig.AddFieldValueHint("attrib11", "file://"+workingDirectory+"/../test/fixtures/forms/hints")
+			Expect(err).NotTo(HaveOccurred())
+
+			hintValues, err := ig.GetFieldValueHints("attrib11")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hintValues).To(Equal([]string{"us-east-1", "us-west-2", "eu-central-1"}))
+		})
+
+		It("accepts hints with any file path", func() {
+
+			for _, hint := range []string{
+				"file:///Users/gopher/regions",
+				"file:///tmp_dir/x",
+				"file://./x",
+				"file://../test/fixtures/forms/hints",
+			} {
+				Expect(ig.AddFieldValueHint("attrib11", hint)).To(Succeed(), hint)
+			}
+			Expect(ig.AddFieldValueHint("attrib11", "ftp://example.com/x")).NotTo(Succeed())
+		})
+
+		It("returns the values of the hints that can be retrieved along with all errors", func() {
+
+			err = ig.AddFieldValueHint("attrib11", "file://./missing1")
+			Expect(err).NotTo(HaveOccurred())
+			err = ig.AddFieldValueHint("attrib11", "file://../test/fixtures/forms/hints")
+			Expect(err).NotTo(HaveOccurred())
+			err = ig.AddFieldValueHint("attrib11", "file://./missing2")
+			Expect(err).NotTo(HaveOccurred())
+
+			hintValues, err := ig.GetFieldValueHints("attrib11")
+			Expect(hintValues).To(Equal([]string{"us-east-1", "us-west-2", "eu-central-1"}))
+			Expect(err).To(MatchError(ContainSubstring("missing1")))
+			Expect(err).To(MatchError(ContainSubstring("missing2")))
+		})
+
+		It("retrieves and caches hint values from an http url", func() {
+
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				fmt.Fprint(w, "t2.micro\r\nt2.small\n")
+			}))
+			defer server.Close()
+
+			err = ig.AddFieldValueHint("attrib12", server.URL+"/skus")
+			Expect(err).NotTo(HaveOccurred())
+
+			hintValues, err := ig.GetFieldValueHints("attrib12")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hintValues).To(Equal([]string{"t2.micro", "t2.small"}))
+
+			hintValues, err = ig.GetFieldValueHints("attrib12")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hintValues).To(Equal([]string{"t2.micro", "t2.small"}))
+			Expect(requests).To(Equal(1))
+		})
+
+		It("returns an error when an http url cannot be retrieved", func() {
+
+			server := httptest.NewServer(http.NotFoundHandler())
+			defer server.Close()
+
+			err = ig.AddFieldValueHint("attrib13", server.URL+"/missing")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = ig.GetFieldValueHints("attrib13")
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
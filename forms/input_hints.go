@@ -0,0 +1,125 @@
+package forms
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mevansam/goutils/logger"
+)
+
+var (
+	// timeout for requests made to http(s)
+	// urls to retrieve field value hints
+	HintRequestTimeout = 10 * time.Second
+
+	// duration for which hint values retrieved
+	// from http(s) urls will be cached
+	HintCacheTTL = 5 * time.Minute
+
+	hintClient = &http.Client{}
+
+	hintCache   = make(map[string]*cachedHint)
+	hintCacheMx sync.Mutex
+)
+
+type cachedHint struct {
+	values  []string
+	expires time.Time
+}
+
+// in: url - http(s) url from which to retrieve newline separated values
+// out: list of values retrieved from the url
+func getHttpHintValues(url string) ([]string, error) {
+
+	var (
+		err error
+
+		req    *http.Request
+		resp   *http.Response
+		values []string
+	)
+
+	// the cache is not locked while the values are
+	// requested so that slow urls do not block the
+	// hints of other fields from being retrieved
+	hintCacheMx.Lock()
+	if cached, exists := hintCache[url]; exists {
+		if time.Now().Before(cached.expires) {
+			hintCacheMx.Unlock()
+			return cached.values, nil
+		}
+		delete(hintCache, url)
+	}
+	hintCacheMx.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), HintRequestTimeout)
+	defer cancel()
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, nil); err != nil {
+		return nil, err
+	}
+	if resp, err = hintClient.Do(req); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"request for hint values from '%s' failed with status: %s",
+			url, resp.Status)
+	}
+	if values, err = readHintValues(resp.Body); err != nil {
+		return nil, err
+	}
+
+	logger.TraceMessage(
+		"Retrieved %d hint values from '%s'.",
+		len(values), url)
+
+	hintCacheMx.Lock()
+	hintCache[url] = &cachedHint{
+		values:  values,
+		expires: time.Now().Add(HintCacheTTL),
+	}
+	hintCacheMx.Unlock()
+
+	return values, nil
+}
+
+// in: path - path to a file from which to read newline separated values
+// out: list of values read from the file
+func getFileHintValues(path string) ([]string, error) {
+
+	var (
+		err  error
+		file *os.File
+	)
+
+	if file, err = os.Open(path); err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readHintValues(file)
+}
+
+// in: reader - reader with newline separated values
+// out: list of non-empty values read
+func readHintValues(reader io.Reader) ([]string, error) {
+
+	values := []string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if value := strings.TrimSpace(scanner.Text()); len(value) > 0 {
+			values = append(values, value)
+		}
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,189 @@
+package forms
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Translations of the texts of an input. texts
+// which are not translated are left empty.
+type Translation struct {
+	DisplayName string `yaml:"displayName,omitempty"`
+	Description string `yaml:"description,omitempty"`
+
+	AcceptedValuesErrorMessage  string `yaml:"acceptedValuesErrorMessage,omitempty"`
+	InclusionFilterErrorMessage string `yaml:"inclusionFilterErrorMessage,omitempty"`
+	ExclusionFilterErrorMessage string `yaml:"exclusionFilterErrorMessage,omitempty"`
+}
+
+// The translations of a form for a locale. inputs
+// are translated by their name and any other text
+// such as the headings and prompts of a TextForm
+// is translated by its default text.
+type Catalog struct {
+	Inputs   map[string]Translation `yaml:"inputs,omitempty"`
+	Messages map[string]string      `yaml:"messages,omitempty"`
+}
+
+// The translation catalogs of a form which
+// are shared by all the inputs of the form.
+// translations are looked up while the form
+// may be locked so they have their own lock.
+type localization struct {
+	locale   string
+	catalogs map[string]*Catalog
+
+	lock sync.RWMutex
+}
+
+// in: path - path to a YAML or JSON catalog file
+// out: the catalog read from the file
+func LoadCatalog(path string) (*Catalog, error) {
+
+	var (
+		err  error
+		data []byte
+	)
+
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, err
+	}
+	catalog := &Catalog{}
+	if err = yaml.Unmarshal(data, catalog); err != nil {
+		return nil, fmt.Errorf("error parsing catalog file '%s': %s", path, err.Error())
+	}
+	return catalog, nil
+}
+
+func newLocalization() *localization {
+	return &localization{
+		catalogs: make(map[string]*Catalog),
+	}
+}
+
+// out: a copy of the localization which
+//      refers to the same catalogs
+func (l *localization) clone() *localization {
+
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	c := newLocalization()
+	c.locale = l.locale
+	for locale, catalog := range l.catalogs {
+		c.catalogs[locale] = catalog
+	}
+	return c
+}
+
+// in: locale  - the locale of the catalog i.e. "de" or "de-CH"
+// in: catalog - the translations of the form for the locale
+func (g *InputGroup) AddCatalog(locale string, catalog *Catalog) {
+	g.localization.lock.Lock()
+	defer g.localization.lock.Unlock()
+	g.localization.catalogs[normalizeLocale(locale)] = catalog
+}
+
+// sets the locale the texts of the form are translated to.
+// translations are looked up in the catalog of the locale
+// and then in the catalog of the locale's language. texts
+// without a translation are shown as is.
+//
+// in: locale - the locale i.e. "de-DE". an empty
+//              locale shows the texts as is.
+func (g *InputGroup) SetLocale(locale string) {
+	g.localization.lock.Lock()
+	defer g.localization.lock.Unlock()
+	g.localization.locale = normalizeLocale(locale)
+}
+
+// out: the locale the texts of the form are translated to
+func (g *InputGroup) Locale() string {
+	g.localization.lock.RLock()
+	defer g.localization.lock.RUnlock()
+	return g.localization.locale
+}
+
+// in: text - a text which is not specific to an input
+// out: the translation of the text to the form's
+//      locale or the text if it has no translation
+func (g *InputGroup) Translate(text string) string {
+	return g.localization.message(text)
+}
+
+// in: name        - name of an input
+// in: text        - returns the text of a translation
+// in: defaultText - the text if it has no translation
+// out: the translation of the input's text
+func (l *localization) input(
+	name string,
+	text func(t Translation) string,
+	defaultText string,
+) string {
+
+	if l == nil {
+		return defaultText
+	}
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	for _, c := range l.lookup() {
+		if t, exists := c.Inputs[name]; exists {
+			if s := text(t); len(s) > 0 {
+				return s
+			}
+		}
+	}
+	return defaultText
+}
+
+// in: text - a text which is not specific to an input
+// out: the translation of the text
+func (l *localization) message(text string) string {
+
+	if l == nil {
+		return text
+	}
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	for _, c := range l.lookup() {
+		if s, exists := c.Messages[text]; exists && len(s) > 0 {
+			return s
+		}
+	}
+	return text
+}
+
+// out: the catalogs translations are looked up in
+func (l *localization) lookup() []*Catalog {
+
+	catalogs := []*Catalog{}
+	if len(l.locale) == 0 {
+		return catalogs
+	}
+	if c, exists := l.catalogs[l.locale]; exists {
+		catalogs = append(catalogs, c)
+	}
+	if i := strings.Index(l.locale, "-"); i != -1 {
+		if c, exists := l.catalogs[l.locale[:i]]; exists {
+			catalogs = append(catalogs, c)
+		}
+	}
+	return catalogs
+}
+
+// in: locale - a locale i.e. "de_DE.UTF-8"
+// out: the locale in the form "de-de"
+func normalizeLocale(locale string) string {
+
+	if i := strings.Index(locale, "."); i != -1 {
+		// drop the encoding
+		locale = locale[:i]
+	}
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
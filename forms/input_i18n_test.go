@@ -0,0 +1,176 @@
+package forms_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mevansam/goforms/forms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	test_data "github.com/mevansam/goforms/test/data"
+)
+
+var _ = Describe("Translations", func() {
+
+	var (
+		err error
+
+		ig *forms.InputGroup
+	)
+
+	BeforeEach(func() {
+
+		ig = test_data.NewTestInputCollection().Group("input-form")
+
+		workingDirectory, err := os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		catalog, err := forms.LoadCatalog(filepath.Join(workingDirectory, "..", "test", "fixtures", "forms", "catalog_de.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		ig.AddCatalog("de", catalog)
+
+		ig.AddCatalog("ja_JP", &forms.Catalog{
+			Inputs: map[string]forms.Translation{
+				"attrib11": {DisplayName: "属性 11"},
+			},
+		})
+	})
+
+	It("translates the texts of inputs to the form's locale", func() {
+
+		attrib11, err := ig.GetInputField("attrib11")
+		Expect(err).NotTo(HaveOccurred())
+		attrib12, err := ig.GetInputField("attrib12")
+		Expect(err).NotTo(HaveOccurred())
+		group1 := ig.Inputs()[0]
+
+		Expect(ig.Locale()).To(BeEmpty())
+		Expect(attrib11.DisplayName()).To(Equal("Attrib 11"))
+
+		// the language's catalog is used for a
+		// locale of the language without a catalog
+		ig.SetLocale("de_DE.UTF-8")
+		Expect(ig.Locale()).To(Equal("de-de"))
+		Expect(attrib11.DisplayName()).To(Equal("Attribut 11"))
+		Expect(attrib11.LongDescription()).To(Equal(
+			"Beschreibung für attrib11. Der Wert wird aus den Umgebungsvariablen " +
+				"ATTRIB11_ENV1, ATTRIB11_ENV2, ATTRIB11_ENV3 gelesen, falls er nicht angegeben wird."))
+		Expect(group1.Description()).To(Equal("Beschreibung für Gruppe 1"))
+		Expect(ig.Translate("Please select one of the above ?")).To(Equal("Bitte wählen Sie eine der obigen Optionen ?"))
+
+		// texts without translations are shown as is
+		Expect(attrib12.DisplayName()).To(Equal("Attribut 12"))
+		Expect(attrib12.Description()).To(Equal("description for attrib12."))
+		Expect(group1.DisplayName()).To(Equal("Group 1"))
+		Expect(ig.Translate("OR")).To(Equal("OR"))
+
+		ig.SetLocale("ja-JP")
+		Expect(attrib11.DisplayName()).To(Equal("属性 11"))
+		Expect(attrib11.Description()).To(Equal("description for attrib11."))
+
+		ig.SetLocale("fr")
+		Expect(attrib11.DisplayName()).To(Equal("Attrib 11"))
+	})
+
+	It("translates validation error messages", func() {
+
+		ig = forms.NewInputCollection().NewGroup("cluster", "cluster settings")
+		_, err = ig.NewInputField(forms.FieldAttributes{
+			Name:                       "provider",
+			AcceptedValues:             []string{"aws", "gcp"},
+			AcceptedValuesErrorMessage: "unknown provider",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		provider, err := ig.GetInputField("provider")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provider.SetValueRef(new(string))).To(Succeed())
+
+		ig.AddCatalog("de", &forms.Catalog{
+			Inputs: map[string]forms.Translation{
+				"provider": {AcceptedValuesErrorMessage: "Unbekannter Anbieter"},
+			},
+		})
+
+		err = ig.SetFieldValue("provider", "azure")
+		Expect(err).To(MatchError("unknown provider"))
+		ig.SetLocale("de")
+		err = ig.SetFieldValue("provider", "azure")
+		Expect(err).To(MatchError("Unbekannter Anbieter"))
+	})
+
+	It("translates the display names of the items of repeatable groups", func() {
+
+		ig = forms.NewInputCollection().NewGroup("cluster", "cluster settings")
+		rg, err := ig.NewRepeatableGroup("nodes", "Node", "the nodes of the cluster", 0, 0)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rg.NewInputField(forms.FieldAttributes{Name: "hostname", DisplayName: "Hostname"})
+		Expect(err).NotTo(HaveOccurred())
+
+		item, err := rg.AddItem()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.DisplayName()).To(Equal("Node #1"))
+
+		ig.AddCatalog("de", &forms.Catalog{
+			Inputs: map[string]forms.Translation{
+				"nodes":    {DisplayName: "Knoten"},
+				"hostname": {DisplayName: "Rechnername"},
+			},
+		})
+		ig.SetLocale("de")
+		Expect(item.DisplayName()).To(Equal("Knoten #1"))
+		Expect(item.InputFields()[0].DisplayName()).To(Equal("Rechnername"))
+	})
+
+	It("shows translations with '%' as they are", func() {
+
+		ig.AddCatalog("fr", &forms.Catalog{
+			Messages: map[string]string{
+				"It will be sourced from the environment variables %s if not provided.": "100% lu depuis %s.",
+			},
+		})
+		ig.SetLocale("fr")
+
+		attrib11, err := ig.GetInputField("attrib11")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attrib11.LongDescription()).To(Equal(
+			"description for attrib11. 100% lu depuis ATTRIB11_ENV1, ATTRIB11_ENV2, ATTRIB11_ENV3."))
+	})
+
+	It("is safe to change catalogs and locales while texts are translated", func() {
+
+		var (
+			wg sync.WaitGroup
+		)
+
+		attrib11, err := ig.GetInputField("attrib11")
+		Expect(err).NotTo(HaveOccurred())
+
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				for i := 0; i < 100; i++ {
+					if w%2 == 0 {
+						ig.AddCatalog(fmt.Sprintf("x%d", i), &forms.Catalog{})
+						ig.SetLocale([]string{"de", "ja-JP"}[i%2])
+					} else {
+						_ = attrib11.LongDescription()
+						_ = ig.Translate("OR")
+						_ = attrib11.DisplayName()
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+	})
+
+	It("reports invalid catalog files", func() {
+		_, err = forms.LoadCatalog(filepath.Join("..", "test", "fixtures", "forms", "hints"))
+		Expect(err).To(HaveOccurred())
+	})
+})
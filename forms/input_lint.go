@@ -0,0 +1,344 @@
+package forms
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// An issue found in the definition of a form
+type LintIssue struct {
+	// name of the input the issue was found in.
+	// inputs of repeatable groups are prefixed
+	// with the name of the group.
+	Input string
+	// description of the issue
+	Message string
+}
+
+// This error is returned when issues are
+// found in the definition of a form
+type FormLintError struct {
+	// name of the form
+	Form string
+	// issues found in the order of the
+	// inputs they were found in
+	Issues []LintIssue
+}
+
+func (e *FormLintError) Error() string {
+
+	var (
+		out strings.Builder
+	)
+
+	out.WriteString(fmt.Sprintf("form '%s' has %d issue(s): ", e.Form, len(e.Issues)))
+	for i, issue := range e.Issues {
+		if i > 0 {
+			out.WriteString("; ")
+		}
+		out.WriteString("input '")
+		out.WriteString(issue.Input)
+		out.WriteString("': ")
+		out.WriteString(issue.Message)
+	}
+	return out.String()
+}
+
+// analyses the dependencies between the inputs of
+// the form and reports dependency cycles, depends
+// on conditions that can never be satisfied by the
+// values accepted by the fields they refer to,
+// containers with less than two inputs, fields that
+// can never be reached and tags of fields that no
+// cursor can ever activate.
+//
+// out: a FormLintError with all issues found or
+//      nil if the form has no issues
+func (g *InputGroup) Lint() error {
+
+	issues := g.lint("")
+	if len(issues) == 0 {
+		return nil
+	}
+	return &FormLintError{
+		Form:   g.name,
+		Issues: issues,
+	}
+}
+
+// in: prefix - prefix of the names of the inputs in issues
+// out: the issues found in the group
+func (g *InputGroup) lint(prefix string) []LintIssue {
+
+	var (
+		walk func(parent *InputField, inputs []Input)
+
+		issues []LintIssue
+	)
+
+	report := func(name, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{
+			Input:   prefix + name,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	// containers are reported in the
+	// order of their ids
+	ids := make([]int, 0, len(g.containers))
+	for id := range g.containers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		c := g.containers[id]
+		switch len(c.inputs) {
+		case 0:
+			report(c.name, "container has no inputs")
+		case 1:
+			report(c.name, "container has only one input '%s'", c.inputs[0].Name())
+		}
+	}
+
+	fields := []*InputField{}
+	repeatables := []*RepeatableGroup{}
+	for _, i := range g.fieldNameSet {
+		switch input := i.(type) {
+		case *InputField:
+			fields = append(fields, input)
+		case *RepeatableGroup:
+			repeatables = append(repeatables, input)
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].order < fields[j].order
+	})
+	sort.Slice(repeatables, func(i, j int) bool {
+		return repeatables[i].order < repeatables[j].order
+	})
+
+	// the fields each field is an input of
+	// in the flow of the form's inputs
+	parents := make(map[*InputField][]*InputField)
+	visited := make(map[*InputField]bool)
+	walk = func(parent *InputField, inputs []Input) {
+		for _, i := range inputs {
+			switch input := i.(type) {
+			case *InputField:
+				if parent != nil {
+					parents[input] = append(parents[input], parent)
+				}
+				if !visited[input] {
+					visited[input] = true
+					walk(input, input.inputs)
+				}
+			case *RepeatableGroup:
+				// inputs of repeatable groups are linted
+				// separately as they are item templates
+			default:
+				walk(parent, i.Inputs())
+			}
+		}
+	}
+	walk(nil, g.inputs)
+
+	for _, cycle := range dependencyCycles(fields, parents) {
+		report(cycle[0].name, "depends on itself via %s", fieldPath(cycle))
+	}
+
+	// fields with conditions that can never be satisfied
+	never := make(map[*InputField]bool)
+	for _, f := range fields {
+		for _, c := range f.postFieldConditions {
+			if !c.expr.satisfiable() {
+				report(f.name,
+					"condition '%s' can never be satisfied by the values accepted by the fields it refers to",
+					c.expr.String())
+				never[f] = true
+			}
+		}
+	}
+
+	reachable := fieldReachability(parents, never)
+	for _, f := range fields {
+		if !visited[f] {
+			report(f.name, "field is not reachable from the inputs of the form")
+		} else if !never[f] && !reachable(f) {
+			report(f.name, "field can never be reached as the inputs it depends on can never be enabled")
+		}
+	}
+
+	for _, f := range fields {
+		for _, tag := range f.tags {
+			if visited[f] && !tagReachable(f, tag, parents, make(map[*InputField]bool)) {
+				report(f.name,
+					"tag '%s' can never be activated as the inputs the field depends on do not have that tag",
+					tag)
+			}
+		}
+	}
+
+	for _, rg := range repeatables {
+		issues = append(issues, rg.InputGroup.lint(prefix+rg.name+".")...)
+	}
+	return issues
+}
+
+// in: fields  - the fields of a form
+// in: parents - the fields each field is an input of
+// out: the cycles in the dependencies between the fields.
+//      each cycle starts and ends with the same field.
+func dependencyCycles(
+	fields []*InputField,
+	parents map[*InputField][]*InputField,
+) [][]*InputField {
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	var (
+		visit func(f *InputField)
+
+		path   []*InputField
+		cycles [][]*InputField
+	)
+
+	state := make(map[*InputField]int)
+	visit = func(f *InputField) {
+
+		state[f] = visiting
+		path = append(path, f)
+
+		// a field depends on the fields it is an
+		// input of and the fields its conditions
+		// refer to
+		depends := append([]*InputField{}, parents[f]...)
+		for _, c := range f.postFieldConditions {
+			depends = append(depends, c.fields...)
+		}
+		for _, d := range depends {
+			switch state[d] {
+			case unvisited:
+				visit(d)
+			case visiting:
+				for i, p := range path {
+					if p == d {
+						cycle := append([]*InputField{}, path[i:]...)
+						cycles = append(cycles, append(cycle, d))
+						break
+					}
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[f] = done
+	}
+	for _, f := range fields {
+		if state[f] == unvisited {
+			visit(f)
+		}
+	}
+	return cycles
+}
+
+// in: parents - the fields each field is an input of
+// in: never   - fields that can never be enabled
+// out: a function that returns whether a field can be
+//      reached via inputs that can be enabled
+func fieldReachability(
+	parents map[*InputField][]*InputField,
+	never map[*InputField]bool,
+) func(f *InputField) bool {
+
+	var (
+		reachable func(f *InputField) bool
+	)
+
+	known := make(map[*InputField]bool)
+	reachable = func(f *InputField) bool {
+
+		if r, exists := known[f]; exists {
+			return r
+		}
+		// guards against dependency cycles
+		known[f] = false
+
+		if never[f] {
+			return false
+		}
+		r := len(parents[f]) == 0
+		for _, p := range parents[f] {
+			if reachable(p) {
+				r = true
+				break
+			}
+		}
+		known[f] = r
+		return r
+	}
+	return reachable
+}
+
+// a field with a tag is only reached by a cursor for
+// that tag if the fields it depends on are reached.
+// fields of containers without the tag cannot be
+// selected so their dependents are not reached.
+//
+// in: f       - the field with the tag
+// in: tag     - the tag to check
+// in: parents - the fields each field is an input of
+// in: visited - fields already checked
+// out: whether the field is reached by a cursor for the tag
+func tagReachable(
+	f *InputField,
+	tag string,
+	parents map[*InputField][]*InputField,
+	visited map[*InputField]bool,
+) bool {
+
+	if len(parents[f]) == 0 {
+		return true
+	}
+	visited[f] = true
+	for _, p := range parents[f] {
+		if visited[p] {
+			continue
+		}
+		if (p.groupId == 0 || hasTag(p, tag)) && tagReachable(p, tag, parents, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// in: f   - a field
+// in: tag - the tag to check
+// out: whether the field is enabled for the tag
+func hasTag(f *InputField, tag string) bool {
+
+	if len(f.tags) == 0 {
+		return true
+	}
+	for _, t := range f.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// in: fields - a path of fields
+// out: the names of the fields in the path
+func fieldPath(fields []*InputField) string {
+
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, "'"+f.name+"'")
+	}
+	return strings.Join(names, " -> ")
+}
@@ -0,0 +1,123 @@
+package forms_test
+
+import (
+	"errors"
+
+	"github.com/mevansam/goforms/forms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	test_data "github.com/mevansam/goforms/test/data"
+)
+
+var _ = Describe("Input Form Lint", func() {
+
+	var (
+		err     error
+		lintErr *forms.FormLintError
+	)
+
+	It("reports tags that no cursor can activate", func() {
+
+		err = test_data.NewTestInputCollection().Group("input-form").Lint()
+		Expect(errors.As(err, &lintErr)).To(BeTrue())
+		Expect(lintErr.Form).To(Equal("input-form"))
+
+		// attrib13 cannot be selected by a cursor for
+		// tag1 so its dependents are never reached
+		Expect(lintErr.Issues).To(Equal([]forms.LintIssue{
+			{
+				Input:   "attrib132",
+				Message: "tag 'tag1' can never be activated as the inputs the field depends on do not have that tag",
+			},
+			{
+				Input:   "attrib133",
+				Message: "tag 'tag1' can never be activated as the inputs the field depends on do not have that tag",
+			},
+		}))
+	})
+
+	It("reports conditions that can never be satisfied and the fields they hide", func() {
+
+		ig := forms.NewInputCollection().NewGroup("cluster", "cluster settings")
+		ig.NewInputContainer("auth", "Authentication", "authentication method", 1)
+
+		rg, err := ig.NewRepeatableGroup("nodes", "Node", "cluster nodes", 1, 0)
+		Expect(err).NotTo(HaveOccurred())
+		rg.NewInputContainer("size", "Size", "node size", 1)
+
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "token", GroupID: 1},
+			{Name: "provider", AcceptedValues: []string{"aws", "azure"}},
+			{Name: "count", AcceptedValues: []string{"1", "3", "5"}},
+			{Name: "project", DependsOn: []string{`provider == "gcp"`}},
+			{Name: "billing", DependsOn: []string{"project"}},
+			{Name: "quorum", DependsOn: []string{`count > 1 and count < 5`}},
+			{Name: "replicas", DependsOn: []string{`count in (2, 4) or provider =~ "^a"`}},
+		} {
+			_, err = ig.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		_, err = rg.NewInputField(forms.FieldAttributes{Name: "instance_type", GroupID: 1})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ig.Lint()
+		Expect(errors.As(err, &lintErr)).To(BeTrue())
+		Expect(lintErr.Issues).To(Equal([]forms.LintIssue{
+			{Input: "auth", Message: "container has only one input 'token'"},
+			{
+				Input:   "project",
+				Message: `condition 'provider == "gcp"' can never be satisfied by the values accepted by the fields it refers to`,
+			},
+			{
+				Input:   "billing",
+				Message: "field can never be reached as the inputs it depends on can never be enabled",
+			},
+			{Input: "nodes.size", Message: "container has only one input 'instance_type'"},
+		}))
+		Expect(err.Error()).To(HavePrefix("form 'cluster' has 4 issue(s): input 'auth': container has only one input 'token'; "))
+	})
+
+	It("reports conditions whose terms can never be satisfied together", func() {
+
+		ig := forms.NewInputCollection().NewGroup("cluster", "cluster settings")
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "region"},
+			{Name: "count", AcceptedValues: []string{"1", "3", "5"}},
+			{Name: "zone", DependsOn: []string{`region == "x" and region == "y"`}},
+			{Name: "quorum", DependsOn: []string{`count > 3 and count < 5`}},
+			{Name: "replicas", DependsOn: []string{`count > 1 and count < 5 and region in ("x", "y") and region != "x"`}},
+		} {
+			_, err = ig.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		err = ig.Lint()
+		Expect(errors.As(err, &lintErr)).To(BeTrue())
+		Expect(lintErr.Issues).To(Equal([]forms.LintIssue{
+			{
+				Input:   "zone",
+				Message: `condition 'region == "x" and region == "y"' can never be satisfied by the values accepted by the fields it refers to`,
+			},
+			{
+				Input:   "quorum",
+				Message: `condition 'count > 3 and count < 5' can never be satisfied by the values accepted by the fields it refers to`,
+			},
+		}))
+	})
+
+	It("passes forms without issues", func() {
+
+		ig := forms.NewInputCollection().NewGroup("cluster", "cluster settings")
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "provider", AcceptedValues: []string{"aws", "azure"}, Tags: []string{"cloud"}},
+			{Name: "region", DependsOn: []string{`provider == "aws"`}, Tags: []string{"cloud"}},
+			{Name: "zone", DependsOn: []string{"region"}, Tags: []string{"cloud", "zonal"}},
+		} {
+			_, err = ig.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(ig.Lint()).To(Succeed())
+	})
+})
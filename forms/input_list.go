@@ -0,0 +1,198 @@
+package forms
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// in: minItems - the minimum number of items the list must have
+// in: maxItems - the maximum number of items the list may have.
+//                0 if the number of items is not limited
+func (f *InputField) SetListInput(minItems, maxItems int) error {
+
+	if minItems < 0 || maxItems < 0 {
+		return fmt.Errorf(
+			"the item counts of list field '%s' cannot be negative",
+			f.name)
+	}
+	if maxItems > 0 && minItems > maxItems {
+		return fmt.Errorf(
+			"the minimum item count of list field '%s' is greater than its maximum item count",
+			f.name)
+	}
+	f.listInput = true
+	f.minItems = minItems
+	f.maxItems = maxItems
+	return nil
+}
+
+// out: whether the field collects a list of values
+func (f *InputField) ListInput() bool {
+	return f.listInput
+}
+
+// out: the minimum number of items of a list field
+func (f *InputField) MinItems() int {
+	return f.minItems
+}
+
+// out: the maximum number of items of a list field.
+//      0 if the number of items is not limited
+func (f *InputField) MaxItems() int {
+	return f.maxItems
+}
+
+// out: the items of a list field or a single item list
+//      with the value of a field that is not a list.
+//      nil if the field does not have a value.
+func (f *InputField) Values() []string {
+
+	var (
+		err   error
+		value *string
+		items []string
+	)
+
+	if value = f.Value(); value == nil {
+		return nil
+	}
+	if !f.listInput {
+		return []string{*value}
+	}
+	if items, err = parseListValue(*value); err != nil {
+		return nil
+	}
+	return items
+}
+
+// in: values - the items to set as the value of a list field
+func (f *InputField) SetValues(values []string) error {
+
+	var (
+		value string
+	)
+
+	if !f.listInput {
+		return fmt.Errorf("field '%s' is not a list field", f.name)
+	}
+	value = formatListValue(values)
+	return f.setValue(&value, bindInput)
+}
+
+// in: item - an item of a list field or the value of a field
+// out: an error if the item is not valid for the field
+func (f *InputField) ValidateItem(item string) error {
+	return f.validateValue(item)
+}
+
+// in: value - the value of a list field
+// out: the value in its canonical form
+// out: an error if any item in the list is invalid or if
+//      the number of items is outside the field's bounds
+func (f *InputField) validateList(value string) (*string, error) {
+
+	var (
+		err   error
+		items []string
+	)
+
+	if items, err = parseListValue(value); err != nil {
+		return nil, fmt.Errorf(
+			"value for field '%s' is not a valid list: %s",
+			f.name, err.Error())
+	}
+	if len(items) < f.minItems {
+		return nil, fmt.Errorf(
+			"field '%s' requires at least %d item(s) but %d were given",
+			f.name, f.minItems, len(items))
+	}
+	if f.maxItems > 0 && len(items) > f.maxItems {
+		return nil, fmt.Errorf(
+			"field '%s' accepts at most %d item(s) but %d were given",
+			f.name, f.maxItems, len(items))
+	}
+	for i, item := range items {
+		if err = f.validateValue(item); err != nil {
+			return nil, fmt.Errorf(
+				"item %d of field '%s' is invalid: %w",
+				i+1, f.name, err)
+		}
+	}
+	value = formatListValue(items)
+	return &value, nil
+}
+
+// in: value - a json array or a comma separated list
+// out: the items in the list
+func parseListValue(value string) ([]string, error) {
+
+	var (
+		err error
+
+		elements []interface{}
+		data     []byte
+	)
+
+	value = strings.TrimSpace(value)
+	items := []string{}
+	if len(value) == 0 {
+		return items, nil
+	}
+
+	if strings.HasPrefix(value, "[") {
+		if err = json.Unmarshal([]byte(value), &elements); err != nil {
+			return nil, err
+		}
+		for _, e := range elements {
+			switch v := e.(type) {
+			case nil:
+				continue
+			case string:
+				items = append(items, v)
+			default:
+				// non-string elements are saved
+				// as their json representation
+				if data, err = json.Marshal(v); err != nil {
+					return nil, err
+				}
+				items = append(items, string(data))
+			}
+		}
+		return items, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		items = append(items, strings.TrimSpace(item))
+	}
+	return items, nil
+}
+
+// in: items - the items of a list
+// out: the canonical json array form of the list
+func formatListValue(items []string) string {
+
+	var (
+		buf strings.Builder
+	)
+
+	if items == nil {
+		items = []string{}
+	}
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	// encoding a string slice cannot fail
+	_ = encoder.Encode(items)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// in: value - the value of a list field
+// out: the value in its canonical form or the
+//      value as is if it is not a valid list
+func canonicalListValue(value string) *string {
+
+	if items, err := parseListValue(value); err == nil {
+		value = formatListValue(items)
+	}
+	return &value
+}
@@ -0,0 +1,153 @@
+package forms_test
+
+import (
+	"errors"
+
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goutils/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("List Input", func() {
+
+	var (
+		err error
+
+		ig    *forms.InputGroup
+		field *forms.InputField
+	)
+
+	BeforeEach(func() {
+
+		ig = forms.NewInputCollection().NewGroup("network", "network settings")
+		_, err = ig.NewInputField(forms.FieldAttributes{
+			Name:                        "dns_servers",
+			DisplayName:                 "DNS Servers",
+			ListInput:                   true,
+			MinItems:                    1,
+			MaxItems:                    3,
+			InclusionFilter:             `^[0-9.]+$`,
+			InclusionFilterErrorMessage: "not an ip address",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = ig.NewInputField(forms.FieldAttributes{
+			Name:        "ports",
+			DisplayName: "Ports",
+			InputType:   forms.Number,
+			ListInput:   true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, f := range ig.InputFields() {
+			err = f.SetValueRef(new(string))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		field, err = ig.GetInputField("dns_servers")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("accepts json arrays and comma separated lists", func() {
+
+		Expect(field.ListInput()).To(BeTrue())
+		Expect(field.MinItems()).To(Equal(1))
+		Expect(field.MaxItems()).To(Equal(3))
+
+		err = ig.SetFieldValue("dns_servers", "1.1.1.1, 8.8.8.8")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*field.Value()).To(Equal(`["1.1.1.1","8.8.8.8"]`))
+		Expect(field.Values()).To(Equal([]string{"1.1.1.1", "8.8.8.8"}))
+
+		err = ig.SetFieldValue("ports", `[80, "443"]`)
+		Expect(err).NotTo(HaveOccurred())
+		err = field.SetValues([]string{"9.9.9.9"})
+		Expect(err).NotTo(HaveOccurred())
+
+		field.SetInput()
+		portsField, _ := ig.GetInputField("ports")
+		portsField.SetInput()
+		Expect(ig.InputValues()).To(Equal(map[string]string{
+			"dns_servers": `["9.9.9.9"]`,
+			"ports":       `["80","443"]`,
+		}))
+	})
+
+	It("validates each item and the number of items", func() {
+
+		var (
+			typeErr *forms.InputTypeError
+		)
+
+		err = ig.SetFieldValue("dns_servers", "1.1.1.1,dns.google")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("item 2 of field 'dns_servers' is invalid: not an ip address"))
+
+		err = ig.SetFieldValue("dns_servers", "")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("field 'dns_servers' requires at least 1 item(s) but 0 were given"))
+
+		err = field.SetValues([]string{"1.1.1.1", "1.0.0.1", "8.8.8.8", "8.8.4.4"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("field 'dns_servers' accepts at most 3 item(s) but 4 were given"))
+
+		err = ig.SetFieldValue("ports", "80,http")
+		Expect(err).To(HaveOccurred())
+		Expect(errors.As(err, &typeErr)).To(BeTrue())
+
+		err = ig.SetFieldValue("ports", `["80"`)
+		Expect(err).To(HaveOccurred())
+
+		Expect(field.ValidateItem("1.1.1.1")).To(Succeed())
+		Expect(field.ValidateItem("dns.google")).NotTo(Succeed())
+		Expect(field.Value()).To(BeNil())
+	})
+
+	It("binds list fields to string slices", func() {
+
+		var servers []string
+		err = field.SetValueRef(&servers)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.Value()).To(BeNil())
+
+		err = field.SetValue(utils.PtrToStr(`["1.1.1.1","8.8.8.8"]`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(servers).To(Equal([]string{"1.1.1.1", "8.8.8.8"}))
+		Expect(*field.Value()).To(Equal(`["1.1.1.1","8.8.8.8"]`))
+	})
+
+	It("keeps items containing commas when bound to string slices", func() {
+
+		var tags []string
+
+		_, err = ig.NewInputField(forms.FieldAttributes{
+			Name:      "tags",
+			ListInput: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		tagsField, err := ig.GetInputField("tags")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tagsField.SetValueRef(&tags)).To(Succeed())
+
+		Expect(tagsField.SetValues([]string{"a,b", "c"})).To(Succeed())
+		Expect(tags).To(Equal([]string{"a,b", "c"}))
+		Expect(tagsField.Values()).To(Equal([]string{"a,b", "c"}))
+		Expect(*tagsField.Value()).To(Equal(`["a,b","c"]`))
+
+		tagsField.SetInput()
+		Expect(ig.InputValueMap()).To(Equal(map[string]interface{}{
+			"tags": []string{"a,b", "c"},
+		}))
+	})
+
+	It("rejects invalid item counts", func() {
+
+		_, err = ig.NewInputField(forms.FieldAttributes{
+			Name:      "tags",
+			ListInput: true,
+			MinItems:  2,
+			MaxItems:  1,
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,166 @@
+package forms
+
+import (
+	"sync"
+)
+
+// A listener which is notified when the value
+// of a field changes. the values are nil if the
+// field had or has no value.
+type ChangeListener func(oldValue, newValue *string)
+
+// The listeners subscribed to changes of a field's value
+type changeObservers struct {
+	lock sync.Mutex
+
+	nextID    int
+	listeners []changeSubscription
+
+	// changes queued in the order they were made
+	// and whether they are being delivered
+	pending    []valueChangeEvent
+	delivering bool
+}
+
+type valueChangeEvent struct {
+	oldValue, newValue *string
+}
+
+type changeSubscription struct {
+	id       int
+	listener ChangeListener
+}
+
+// subscribes a listener to changes of the field's value.
+// listeners are called in the order they subscribed once
+// the new value has been set by SetValue, SetFieldValue
+// or an InputCursor. changes are delivered one at a time
+// in the order they were made even when the field is set
+// concurrently, so a change may be delivered by the call
+// that set the field before it.
+//
+// in: listener - the listener to notify
+// out: a function which unsubscribes the listener
+func (f *InputField) OnChange(listener ChangeListener) func() {
+
+	o := &f.observers
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.nextID++
+	id := o.nextID
+	o.listeners = append(o.listeners, changeSubscription{id, listener})
+
+	return func() {
+		o.lock.Lock()
+		defer o.lock.Unlock()
+
+		for i, s := range o.listeners {
+			if s.id == id {
+				o.listeners = append(o.listeners[:i:i], o.listeners[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// subscribes a listener to changes of the value of a field of the group
+//
+// in: name     - name of the field
+// in: listener - the listener to notify
+// out: a function which unsubscribes the listener
+func (g *InputGroup) OnChange(name string, listener ChangeListener) (func(), error) {
+
+	var (
+		err   error
+		field *InputField
+	)
+
+	if field, err = g.GetInputField(name); err != nil {
+		return nil, err
+	}
+	return field.OnChange(listener), nil
+}
+
+// out: whether any listeners are subscribed to the field's changes
+func (f *InputField) hasChangeListeners() bool {
+
+	f.observers.lock.Lock()
+	defer f.observers.lock.Unlock()
+	return len(f.observers.listeners) > 0
+}
+
+// queues a notification of the field's listeners if its
+// value has changed. it must be called while holding the
+// form's lock so that changes are queued in the order
+// they were made.
+//
+// in: oldValue - the value of the field before it was set
+// in: newValue - the value of the field after it was set
+func (f *InputField) queueChange(oldValue, newValue *string) {
+
+	if oldValue == newValue ||
+		(oldValue != nil && newValue != nil && *oldValue == *newValue) {
+		return
+	}
+
+	f.observers.lock.Lock()
+	defer f.observers.lock.Unlock()
+	f.observers.pending = append(f.observers.pending, valueChangeEvent{oldValue, newValue})
+}
+
+// notifies the field's listeners of the queued changes.
+// if the changes are already being delivered by another
+// call the changes are left to that call, which ensures
+// that listeners are never notified out of order.
+func (f *InputField) notifyChanges() {
+
+	o := &f.observers
+	o.lock.Lock()
+	if o.delivering {
+		o.lock.Unlock()
+		return
+	}
+	o.delivering = true
+
+	// delivery is ended even if a listener panics
+	// so that later changes are still delivered
+	locked := true
+	defer func() {
+		if !locked {
+			o.lock.Lock()
+		}
+		o.delivering = false
+		o.lock.Unlock()
+	}()
+
+	for len(o.pending) > 0 {
+		event := o.pending[0]
+		o.pending = o.pending[1:]
+
+		// listeners are called without holding the lock
+		// so they can subscribe or unsubscribe listeners
+		// and set the field's value
+		listeners := make([]changeSubscription, len(o.listeners))
+		copy(listeners, o.listeners)
+		o.lock.Unlock()
+		locked = false
+
+		for _, s := range listeners {
+			s.listener(event.oldValue, event.newValue)
+		}
+		o.lock.Lock()
+		locked = true
+	}
+}
+
+// in: value - a value which may refer to a bound value object
+// out: a copy of the value
+func copyValue(value *string) *string {
+
+	if value == nil {
+		return nil
+	}
+	v := *value
+	return &v
+}
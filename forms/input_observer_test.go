@@ -0,0 +1,201 @@
+package forms_test
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mevansam/goforms/forms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	test_data "github.com/mevansam/goforms/test/data"
+)
+
+var _ = Describe("Input Change Observers", func() {
+
+	var (
+		err error
+
+		ig      *forms.InputGroup
+		changes []string
+	)
+
+	// out: listener which records changes of the named field
+	record := func(name string) forms.ChangeListener {
+		return func(oldValue, newValue *string) {
+			s := func(v *string) string {
+				if v == nil {
+					return "<nil>"
+				}
+				return *v
+			}
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", name, s(oldValue), s(newValue)))
+		}
+	}
+
+	BeforeEach(func() {
+		ig = test_data.NewTestInputCollection().Group("input-form")
+		for _, f := range ig.InputFields() {
+			err = f.SetValueRef(new(string))
+			Expect(err).ToNot(HaveOccurred())
+		}
+		changes = nil
+	})
+
+	It("notifies listeners when values change", func() {
+
+		_, err = ig.OnChange("attrib12", record("attrib12"))
+		Expect(err).ToNot(HaveOccurred())
+		unsubscribe, err := ig.OnChange("attrib122", record("attrib122"))
+		Expect(err).ToNot(HaveOccurred())
+
+		field, err := ig.GetInputField("attrib12")
+		Expect(err).ToNot(HaveOccurred())
+		value := "value A"
+		Expect(field.SetValue(&value)).To(Succeed())
+		Expect(ig.SetFieldValue("attrib12", "value B")).To(Succeed())
+		// setting the same value again is not a change
+		Expect(ig.SetFieldValue("attrib12", "value B")).To(Succeed())
+
+		cursor := forms.NewInputCursor(ig, "tag1").NextInput()
+		cursor, err = cursor.SetInput("attrib12", "value for attrib12 - B")
+		Expect(err).ToNot(HaveOccurred())
+		cursor = cursor.NextInput()
+		_, err = cursor.SetInput("attrib122", "value D")
+		Expect(err).ToNot(HaveOccurred())
+
+		unsubscribe()
+		Expect(ig.SetFieldValue("attrib122", "value E")).To(Succeed())
+
+		Expect(changes).To(Equal([]string{
+			"attrib12: <nil> -> value A",
+			"attrib12: value A -> value B",
+			"attrib12: value B -> value for attrib12 - B",
+			"attrib122: <nil> -> value D",
+		}))
+	})
+
+	It("returns an error when subscribing to an unknown field", func() {
+		_, err = ig.OnChange("unknown", record("unknown"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("is safe for listeners which subscribe concurrently", func() {
+
+		var (
+			wg    sync.WaitGroup
+			lock  sync.Mutex
+			count int
+		)
+
+		field, err := ig.GetInputField("attrib11")
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					unsubscribe := field.OnChange(func(oldValue, newValue *string) {
+						lock.Lock()
+						count++
+						lock.Unlock()
+					})
+					unsubscribe()
+				}
+			}()
+		}
+		// a listener which stays subscribed
+		field.OnChange(func(oldValue, newValue *string) {
+			lock.Lock()
+			count++
+			lock.Unlock()
+		})
+		wg.Wait()
+
+		Expect(ig.SetFieldValue("attrib11", "value")).To(Succeed())
+		Expect(count).To(Equal(1))
+	})
+
+	It("delivers changes made concurrently in the order they were made", func() {
+
+		var (
+			wg   sync.WaitGroup
+			lock sync.Mutex
+
+			events [][2]string
+		)
+
+		field, err := ig.GetInputField("attrib11")
+		Expect(err).ToNot(HaveOccurred())
+		field.OnChange(func(oldValue, newValue *string) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			event := [2]string{"", *newValue}
+			if oldValue != nil {
+				event[0] = *oldValue
+			}
+			events = append(events, event)
+		})
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					Expect(ig.SetFieldValue("attrib11", fmt.Sprintf("value %d-%d", i, j))).To(Succeed())
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		// each change starts from the value
+		// the previous change ended with
+		Expect(events).To(HaveLen(1000))
+		for i := 1; i < len(events); i++ {
+			Expect(events[i][0]).To(Equal(events[i-1][1]))
+		}
+		Expect(*field.Value()).To(Equal(events[len(events)-1][1]))
+	})
+
+	It("delivers changes made by listeners after the change being delivered", func() {
+
+		field, err := ig.GetInputField("attrib11")
+		Expect(err).ToNot(HaveOccurred())
+		field.OnChange(func(oldValue, newValue *string) {
+			if *newValue == "value A" {
+				Expect(ig.SetFieldValue("attrib11", "value B")).To(Succeed())
+			}
+		})
+		field.OnChange(record("attrib11"))
+
+		Expect(ig.SetFieldValue("attrib11", "value A")).To(Succeed())
+		Expect(changes).To(Equal([]string{
+			"attrib11: <nil> -> value A",
+			"attrib11: value A -> value B",
+		}))
+	})
+
+	It("delivers changes made after a listener panics", func() {
+
+		field, err := ig.GetInputField("attrib11")
+		Expect(err).ToNot(HaveOccurred())
+		field.OnChange(record("attrib11"))
+		field.OnChange(func(oldValue, newValue *string) {
+			if *newValue == "value A" {
+				panic("listener failed")
+			}
+		})
+
+		Expect(func() {
+			_ = ig.SetFieldValue("attrib11", "value A")
+		}).To(PanicWith("listener failed"))
+		Expect(ig.SetFieldValue("attrib11", "value B")).To(Succeed())
+		Expect(changes).To(Equal([]string{
+			"attrib11: <nil> -> value A",
+			"attrib11: value A -> value B",
+		}))
+	})
+})
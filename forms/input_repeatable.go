@@ -0,0 +1,416 @@
+package forms
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// This structure defines a sub-group of inputs that
+// can be repeated to collect a list of structured
+// items. The inputs added to the sub-group are a
+// template from which an input group is created for
+// each item in the list. It implements the Input
+// abstraction.
+type RepeatableGroup struct {
+	InputGroup
+
+	// order in which the group was
+	// added to its input form
+	order int
+
+	minItems,
+	maxItems int
+
+	items []*InputGroup
+
+	// pointer to a slice of structs
+	// the items are bound to
+	valueRef interface{}
+
+	// serializes binding the items
+	// to the elements of the slice
+	bindMx sync.Mutex
+}
+
+// in: name        - name of the repeatable group
+// in: displayName - the name to display when requesting input
+// in: description - a long description which can also be
+//                     the help text for the group
+// in: minItems    - the minimum number of items to collect
+// in: maxItems    - the maximum number of items to collect.
+//                   0 if the number of items is not limited
+// out: An initialized instance of a RepeatableGroup structure
+//      whose template inputs can be added via NewInputField
+func (g *InputGroup) NewRepeatableGroup(
+	name, displayName, description string,
+	minItems, maxItems int,
+) (*RepeatableGroup, error) {
+
+	g.igMx.Lock()
+	defer g.igMx.Unlock()
+
+	// Do not allow adding duplicate inputs
+	if _, exists := g.fieldNameSet[name]; exists {
+		return nil, fmt.Errorf(
+			"a field with name '%s' has already been added",
+			name)
+	}
+	if minItems < 0 || maxItems < 0 || (maxItems > 0 && minItems > maxItems) {
+		return nil, fmt.Errorf(
+			"repeatable group '%s' has invalid item counts: min %d, max %d",
+			name, minItems, maxItems)
+	}
+
+	rg := &RepeatableGroup{
+		InputGroup: InputGroup{
+			name:        name,
+			description: description,
+
+			displayName: displayName,
+			inputs:      []Input{},
+
+			containers:   make(map[int]*InputGroup),
+			fieldNameSet: make(map[string]Input),
+
+			fieldValueLookupHints: make(map[string][]string),
+
+			localization: g.localization,
+			secrets:      g.secrets,
+			igMx:         g.igMx,
+		},
+		order: len(g.fieldNameSet),
+
+		minItems: minItems,
+		maxItems: maxItems,
+		items:    []*InputGroup{},
+	}
+	g.fieldNameSet[name] = rg
+	g.inputs = append(g.inputs, rg)
+
+	return rg, nil
+}
+
+// in: name - the name of the repeatable group to retrieve
+// out: the repeatable group with the given name
+func (g *InputGroup) GetRepeatableGroup(name string) (*RepeatableGroup, error) {
+
+	var (
+		input Input
+		rg    *RepeatableGroup
+		ok    bool
+	)
+
+	g.igMx.RLock()
+	defer g.igMx.RUnlock()
+
+	if input, ok = g.fieldNameSet[name]; !ok {
+		return nil, fmt.Errorf("repeatable group '%s' was not found in form", name)
+	}
+	if rg, ok = input.(*RepeatableGroup); !ok {
+		return nil, fmt.Errorf("input '%s' is not a repeatable group", name)
+	}
+	return rg, nil
+}
+
+// out: returns input type of "Repeatable"
+func (rg *RepeatableGroup) Type() InputType {
+	return Repeatable
+}
+
+// out: the minimum number of items to collect
+func (rg *RepeatableGroup) MinItems() int {
+	return rg.minItems
+}
+
+// out: the maximum number of items to collect.
+//      0 if the number of items is not limited
+func (rg *RepeatableGroup) MaxItems() int {
+	return rg.maxItems
+}
+
+// out: the input groups of the items collected
+func (rg *RepeatableGroup) Items() []*InputGroup {
+
+	rg.igMx.RLock()
+	defer rg.igMx.RUnlock()
+	return append([]*InputGroup{}, rg.items...)
+}
+
+// in: valueRef - pointer to a slice of structs. an item will
+//                be created for each element of the slice and
+//                the element's fields bound to the item's fields
+//                via their "form_field" tags.
+func (rg *RepeatableGroup) SetValueRef(valueRef interface{}) error {
+
+	var (
+		err error
+
+		item *InputGroup
+	)
+
+	v := reflect.ValueOf(valueRef)
+	if v.Kind() != reflect.Ptr ||
+		v.Elem().Kind() != reflect.Slice ||
+		v.Elem().Type().Elem().Kind() != reflect.Struct {
+
+		return fmt.Errorf(
+			"the repeatable group '%s' value reference must be a pointer to a slice of structs",
+			rg.name)
+	}
+
+	items := make([]*InputGroup, 0, v.Elem().Len())
+	for i := 0; i < v.Elem().Len(); i++ {
+		if item, err = rg.newItem(); err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+
+	rg.igMx.Lock()
+	rg.valueRef = valueRef
+	rg.items = items
+	rg.numberItems()
+	rg.igMx.Unlock()
+
+	return rg.bindItems()
+}
+
+// out: the input group of a new item appended to the list
+func (rg *RepeatableGroup) AddItem() (*InputGroup, error) {
+
+	var (
+		err  error
+		item *InputGroup
+	)
+
+	if item, err = rg.newItem(); err != nil {
+		return nil, err
+	}
+
+	// the number of items is checked when the
+	// item is appended so that items added
+	// concurrently cannot exceed the maximum
+	rg.igMx.Lock()
+	if rg.maxItems > 0 && len(rg.items) >= rg.maxItems {
+		rg.igMx.Unlock()
+		return nil, fmt.Errorf(
+			"no more than %d item(s) can be added to '%s'",
+			rg.maxItems, rg.name)
+	}
+	rg.items = append(rg.items, item)
+	rg.numberItems()
+
+	if rg.valueRef != nil {
+		slice := reflect.ValueOf(rg.valueRef).Elem()
+		slice.Set(reflect.Append(slice, reflect.Zero(slice.Type().Elem())))
+	}
+	rg.igMx.Unlock()
+
+	// the slice may have been reallocated so all
+	// items need to be bound to the new elements
+	if err = rg.bindItems(); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// in: index - index of the item to remove
+func (rg *RepeatableGroup) RemoveItem(index int) error {
+
+	rg.igMx.Lock()
+	if index < 0 || index >= len(rg.items) {
+		rg.igMx.Unlock()
+		return fmt.Errorf(
+			"item %d of '%s' does not exist",
+			index, rg.name)
+	}
+	rg.items = append(rg.items[:index:index], rg.items[index+1:]...)
+
+	if rg.valueRef != nil {
+		slice := reflect.ValueOf(rg.valueRef).Elem()
+		reflect.Copy(slice.Slice(index, slice.Len()), slice.Slice(index+1, slice.Len()))
+		slice.Set(slice.Slice(0, slice.Len()-1))
+	}
+	rg.numberItems()
+	rg.igMx.Unlock()
+
+	return rg.bindItems()
+}
+
+// out: a new item created from the template inputs
+//      which has not been added to the list
+func (rg *RepeatableGroup) newItem() (*InputGroup, error) {
+
+	item := &InputGroup{
+		name:        rg.name,
+		description: rg.description,
+		inputs:      []Input{},
+
+		containers:   make(map[int]*InputGroup),
+		fieldNameSet: make(map[string]Input),
+
+		fieldValueLookupHints: make(map[string][]string),
+
+		localization: rg.localization,
+		secrets:      rg.secrets,
+		igMx:         rg.igMx,
+	}
+	if err := rg.InputGroup.copyDefinition(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// updates the display names of the items
+// to reflect their position in the list
+func (rg *RepeatableGroup) numberItems() {
+	for i, item := range rg.items {
+		item.displayName = rg.displayName
+		item.itemNumber = i + 1
+	}
+}
+
+// binds the fields of each item to the element
+// of the bound slice at the same position. fields
+// that are not bound are bound to a new string.
+func (rg *RepeatableGroup) bindItems() error {
+
+	var (
+		err error
+	)
+
+	// items are bound one call at a time so that the
+	// last call binds them to the slice as it is once
+	// items added or removed concurrently are in it
+	rg.bindMx.Lock()
+	defer rg.bindMx.Unlock()
+
+	rg.igMx.RLock()
+	items := append([]*InputGroup{}, rg.items...)
+	elements := make([]interface{}, 0, len(items))
+	if rg.valueRef != nil {
+		slice := reflect.ValueOf(rg.valueRef).Elem()
+		for i := range items {
+			elements = append(elements, slice.Index(i).Addr().Interface())
+		}
+	}
+	rg.igMx.RUnlock()
+
+	for i, item := range items {
+		if len(elements) > 0 {
+			if err = item.BindFields(elements[i]); err != nil {
+				return err
+			}
+		}
+		for _, f := range item.InputFields() {
+			if f.valueRef == nil {
+				if err = f.SetValueRef(new(string)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// out: the values entered for each item
+func (rg *RepeatableGroup) itemValues() []interface{} {
+
+	values := make([]interface{}, 0, len(rg.items))
+	for _, item := range rg.items {
+		values = append(values, item.inputValueMap())
+	}
+	return values
+}
+
+// in: target - an empty input group to which copies of this
+//              group's containers, fields and repeatable
+//              groups will be added
+func (g *InputGroup) copyDefinition(target *InputGroup) error {
+
+	var (
+		err error
+
+		field *InputField
+		rg    *RepeatableGroup
+	)
+
+	for id, c := range g.containers {
+		target.NewInputContainer(c.name, c.displayName, c.description, id)
+	}
+
+	// inputs are copied in the order they were
+	// added so dependencies are added before
+	// the fields that depend on them
+	inputs := make([]Input, 0, len(g.fieldNameSet))
+	for _, i := range g.fieldNameSet {
+		inputs = append(inputs, i)
+	}
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputOrder(inputs[i]) < inputOrder(inputs[j])
+	})
+
+	for _, i := range inputs {
+		switch input := i.(type) {
+
+		case *InputField:
+			if field, err = target.newInputField(
+				input.name,
+				input.displayName,
+				input.description,
+				input.groupId,
+				input.inputType,
+				input.valueFromFile,
+				input.defaultValue,
+				input.sensitive,
+				input.envVars,
+				input.dependsOn,
+				input.tags,
+			); err != nil {
+				return err
+			}
+			field.inclusionFilter = input.inclusionFilter
+			field.inclusionFilterErrorMessage = input.inclusionFilterErrorMessage
+			field.exclusionFilter = input.exclusionFilter
+			field.exclusionFilterErrorMessage = input.exclusionFilterErrorMessage
+			field.SetAcceptedValues(input.acceptedValues, input.acceptedValuesErrorMessage)
+			field.listInput = input.listInput
+			field.minItems = input.minItems
+			field.maxItems = input.maxItems
+
+		case *RepeatableGroup:
+			if rg, err = target.NewRepeatableGroup(
+				input.name,
+				input.displayName,
+				input.description,
+				input.minItems,
+				input.maxItems,
+			); err != nil {
+				return err
+			}
+			if err = input.InputGroup.copyDefinition(&rg.InputGroup); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, hints := range g.fieldValueLookupHints {
+		target.fieldValueLookupHints[name] = append([]string{}, hints...)
+	}
+	target.validators = append(target.validators, g.validators...)
+	return nil
+}
+
+// out: the order in which the input was added to its form
+func inputOrder(input Input) int {
+
+	switch i := input.(type) {
+	case *InputField:
+		return i.order
+	case *RepeatableGroup:
+		return i.order
+	}
+	return 0
+}
@@ -0,0 +1,186 @@
+package forms_test
+
+import (
+	"bytes"
+
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goutils/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type networkInterface struct {
+	Name    string  `form_field:"name"`
+	IP      string  `form_field:"ip"`
+	Gateway *string `form_field:"gateway"`
+}
+
+type networkConfig struct {
+	Hostname   string             `form_field:"hostname"`
+	Interfaces []networkInterface `form_field:"interfaces"`
+}
+
+var _ = Describe("Repeatable Groups", func() {
+
+	var (
+		err error
+
+		ic *forms.InputCollection
+		ig *forms.InputGroup
+		rg *forms.RepeatableGroup
+	)
+
+	BeforeEach(func() {
+
+		ic = forms.NewInputCollection()
+		ig = ic.NewGroup("network", "network settings")
+		_, err = ig.NewInputField(forms.FieldAttributes{
+			Name:        "hostname",
+			DisplayName: "Hostname",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		rg, err = ig.NewRepeatableGroup("interfaces", "Interface", "network interfaces", 1, 2)
+		Expect(err).NotTo(HaveOccurred())
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "name", DisplayName: "Name"},
+			{
+				Name:                        "ip",
+				DisplayName:                 "IP",
+				InclusionFilter:             `^[0-9.]+$`,
+				InclusionFilterErrorMessage: "not an ip address",
+			},
+			{Name: "gateway", DisplayName: "Gateway", DefaultValue: utils.PtrToStr("10.0.0.1")},
+		} {
+			_, err = rg.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("binds items to a slice of structs", func() {
+
+		config := networkConfig{
+			Interfaces: []networkInterface{
+				{Name: "eth0", IP: "10.0.0.2"},
+			},
+		}
+		err = ig.BindFields(&config)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(len(rg.Items())).To(Equal(1))
+		Expect(rg.Items()[0].DisplayName()).To(Equal("Interface #1"))
+		value, err := rg.Items()[0].GetFieldValue("ip")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*value).To(Equal("10.0.0.2"))
+
+		item, err := rg.AddItem()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(item.SetFieldValue("name", "eth1")).To(Succeed())
+		Expect(item.SetFieldValue("ip", "10.0.1.2")).To(Succeed())
+		Expect(item.SetFieldValue("ip", "ten")).NotTo(Succeed())
+
+		_, err = rg.AddItem()
+		Expect(err).To(HaveOccurred())
+
+		Expect(len(config.Interfaces)).To(Equal(2))
+		Expect(config.Interfaces[0].Name).To(Equal("eth0"))
+		Expect(*config.Interfaces[0].Gateway).To(Equal("10.0.0.1"))
+		Expect(config.Interfaces[1].IP).To(Equal("10.0.1.2"))
+
+		err = rg.RemoveItem(0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(config.Interfaces)).To(Equal(1))
+		Expect(config.Interfaces[0].Name).To(Equal("eth1"))
+		Expect(rg.Items()[0].DisplayName()).To(Equal("Interface #1"))
+
+		// the remaining item remains bound
+		Expect(rg.Items()[0].SetFieldValue("name", "eth2")).To(Succeed())
+		Expect(config.Interfaces[0].Name).To(Equal("eth2"))
+	})
+
+	It("steps through one item at a time with a cursor", func() {
+
+		field, err := ig.GetInputField("hostname")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.SetValueRef(new(string))).To(Succeed())
+
+		cursor := forms.NewInputCursor(ig)
+		cursor = cursor.NextInput()
+		cursor, err = cursor.SetInput("hostname", "host1")
+		Expect(err).NotTo(HaveOccurred())
+
+		cursor = cursor.NextInput()
+		input, err := cursor.GetCurrentInput()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(input.Type()).To(Equal(forms.Repeatable))
+
+		for _, values := range [][]string{
+			{"eth0", "10.0.0.2"},
+			{"eth1", "10.0.1.2"},
+		} {
+			cursor, err = cursor.AddItem("interfaces")
+			Expect(err).NotTo(HaveOccurred())
+
+			cursor = cursor.NextInput()
+			cursor, err = cursor.SetInput("name", values[0])
+			Expect(err).NotTo(HaveOccurred())
+			cursor = cursor.NextInput()
+			cursor, err = cursor.SetInput("ip", values[1])
+			Expect(err).NotTo(HaveOccurred())
+			cursor = cursor.NextInput()
+			cursor, err = cursor.SetDefaultInput("gateway")
+			Expect(err).NotTo(HaveOccurred())
+
+			// cursor returns to the repeatable group
+			cursor = cursor.NextInput()
+			input, err = cursor.GetCurrentInput()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(input.Name()).To(Equal("interfaces"))
+		}
+
+		_, err = cursor.AddItem("interfaces")
+		Expect(err).To(HaveOccurred())
+
+		cursor, err = cursor.SelectItem("interfaces", 0)
+		Expect(err).NotTo(HaveOccurred())
+		cursor = cursor.NextInput()
+		input, err = cursor.GetCurrentInput()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(input.Name()).To(Equal("name"))
+
+		Expect(ig.InputValues()).To(Equal(map[string]string{
+			"hostname":   "host1",
+			"interfaces": `[{"gateway":"10.0.0.1","ip":"10.0.0.2","name":"eth0"},{"gateway":"10.0.0.1","ip":"10.0.1.2","name":"eth1"}]`,
+		}))
+	})
+
+	It("exports repeatable groups to documents and schemas", func() {
+
+		var (
+			exported bytes.Buffer
+		)
+
+		err = ic.Export(&exported, forms.YAML)
+		Expect(err).NotTo(HaveOccurred())
+
+		loaded, err := forms.LoadCollection(bytes.NewReader(exported.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Group("network").String()).To(Equal(ig.String()))
+
+		lrg, err := loaded.Group("network").GetRepeatableGroup("interfaces")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lrg.MinItems()).To(Equal(1))
+		Expect(lrg.MaxItems()).To(Equal(2))
+		Expect(len(lrg.Inputs())).To(Equal(3))
+
+		schema := ig.JSONSchema()
+		Expect(schema["required"]).To(Equal([]string{"hostname", "interfaces"}))
+
+		property := schema["properties"].(map[string]interface{})["interfaces"].(map[string]interface{})
+		Expect(property["type"]).To(Equal("array"))
+		Expect(property["minItems"]).To(Equal(1))
+		Expect(property["maxItems"]).To(Equal(2))
+		Expect(property["items"].(map[string]interface{})["required"]).To(Equal([]string{"name", "ip"}))
+	})
+})
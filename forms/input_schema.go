@@ -0,0 +1,290 @@
+package forms
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// out: a JSON Schema (draft 2020-12) document describing
+//      the values that can be collected by the form
+func (g *InputGroup) JSONSchema() map[string]interface{} {
+
+	var (
+		walk func(input Input, parent *InputField)
+	)
+
+	properties := make(map[string]interface{})
+	required := []string{}
+	rules := []interface{}{}
+
+	visited := make(map[string]bool)
+	walk = func(input Input, parent *InputField) {
+
+		for _, i := range input.Inputs() {
+
+			if i.Type() == Container {
+				container := i.(*InputGroup)
+				if parent == nil {
+					rules = append(rules, container.jsonSchemaOneOf())
+				} else {
+					rules = append(rules, map[string]interface{}{
+						"if":   map[string]interface{}{"required": []string{parent.name}},
+						"then": container.jsonSchemaOneOf(),
+					})
+				}
+				for _, m := range container.inputs {
+					f := m.(*InputField)
+					if !visited[f.name] {
+						visited[f.name] = true
+						properties[f.name] = f.jsonSchemaProperty()
+						rules = append(rules, f.jsonSchemaConditions()...)
+						walk(f, f)
+					}
+				}
+				continue
+			}
+
+			if i.Type() == Repeatable {
+				rg := i.(*RepeatableGroup)
+				if rg.minItems > 0 {
+					if parent == nil {
+						required = append(required, rg.name)
+					} else {
+						rules = append(rules, map[string]interface{}{
+							"if":   map[string]interface{}{"required": []string{parent.name}},
+							"then": map[string]interface{}{"required": []string{rg.name}},
+						})
+					}
+				}
+				properties[rg.name] = rg.jsonSchemaProperty()
+				continue
+			}
+
+			f := i.(*InputField)
+			if !f.Optional() && len(f.postFieldConditions) == 0 {
+				// fields with value conditions are
+				// required via their condition rules
+				if parent == nil {
+					required = append(required, f.name)
+				} else {
+					rules = append(rules, map[string]interface{}{
+						"if":   map[string]interface{}{"required": []string{parent.name}},
+						"then": map[string]interface{}{"required": []string{f.name}},
+					})
+				}
+			}
+			if !visited[f.name] {
+				visited[f.name] = true
+				properties[f.name] = f.jsonSchemaProperty()
+				rules = append(rules, f.jsonSchemaConditions()...)
+				walk(f, f)
+			}
+		}
+	}
+	walk(g, nil)
+
+	schema := map[string]interface{}{
+		"$schema":              jsonSchemaDraft,
+		"title":                g.name,
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(g.description) > 0 {
+		schema["description"] = g.description
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if len(rules) > 0 {
+		schema["allOf"] = rules
+	}
+	return schema
+}
+
+// in: writer - writer to which the form's JSON Schema will be written
+func (g *InputGroup) WriteJSONSchema(writer io.Writer) error {
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g.JSONSchema())
+}
+
+// out: a schema which requires exactly one of the container's
+//      inputs. if any input is conditional on the value of
+//      another field then none of the inputs may be present
+//      as the condition may disable all of them.
+func (g *InputGroup) jsonSchemaOneOf() map[string]interface{} {
+
+	alternatives := []interface{}{}
+	anyOf := []interface{}{}
+	conditional := false
+
+	for _, i := range g.inputs {
+		alternatives = append(alternatives, map[string]interface{}{
+			"required": []string{i.Name()},
+		})
+		anyOf = append(anyOf, map[string]interface{}{
+			"required": []string{i.Name()},
+		})
+		if f, ok := i.(*InputField); ok && len(f.postFieldConditions) > 0 {
+			conditional = true
+		}
+	}
+	if conditional {
+		alternatives = append(alternatives, map[string]interface{}{
+			"not": map[string]interface{}{"anyOf": anyOf},
+		})
+	}
+	return map[string]interface{}{"oneOf": alternatives}
+}
+
+// out: the schema of the field's value
+func (f *InputField) jsonSchemaProperty() map[string]interface{} {
+
+	property := map[string]interface{}{
+		"type": "string",
+	}
+	// the constraints of a list field
+	// apply to each item in the list
+	item := property
+	if f.listInput {
+		property = map[string]interface{}{
+			"type":  "array",
+			"items": item,
+		}
+		if f.minItems > 0 {
+			property["minItems"] = f.minItems
+		}
+		if f.maxItems > 0 {
+			property["maxItems"] = f.maxItems
+		}
+	}
+	if len(f.displayName) > 0 {
+		property["title"] = f.displayName
+	}
+	if len(f.description) > 0 {
+		property["description"] = f.description
+	}
+
+	switch f.inputType {
+	case Number:
+		item["type"] = "number"
+	case HttpUrl:
+		item["format"] = "uri"
+	case EmailAddress:
+		item["format"] = "email"
+	case JsonInput:
+		item["contentMediaType"] = "application/json"
+	}
+
+	if f.defaultValue != nil {
+		property["default"] = f.jsonSchemaDefault()
+	}
+	if len(f.acceptedValues) > 0 {
+		enum := make([]interface{}, 0, len(f.acceptedValues))
+		for _, v := range f.acceptedValues {
+			enum = append(enum, f.jsonSchemaValue(v))
+		}
+		item["enum"] = enum
+	}
+	// patterns only apply to string values so
+	// filters of number fields are not mapped
+	if f.inputType != Number {
+		if f.inclusionFilter != nil {
+			item["pattern"] = f.inclusionFilter.String()
+		}
+		if f.exclusionFilter != nil {
+			item["not"] = map[string]interface{}{
+				"pattern": f.exclusionFilter.String(),
+			}
+		}
+	}
+	if f.sensitive {
+		property["writeOnly"] = true
+	}
+	return property
+}
+
+// out: the schema of the repeatable group's items
+func (rg *RepeatableGroup) jsonSchemaProperty() map[string]interface{} {
+
+	items := rg.InputGroup.JSONSchema()
+	delete(items, "$schema")
+	delete(items, "title")
+	delete(items, "description")
+
+	property := map[string]interface{}{
+		"type":  "array",
+		"items": items,
+	}
+	if len(rg.displayName) > 0 {
+		property["title"] = rg.displayName
+	}
+	if len(rg.description) > 0 {
+		property["description"] = rg.description
+	}
+	if rg.minItems > 0 {
+		property["minItems"] = rg.minItems
+	}
+	if rg.maxItems > 0 {
+		property["maxItems"] = rg.maxItems
+	}
+	return property
+}
+
+// out: if/then rules for each of the field's depends on
+//      conditions. when a condition is satisfied the field
+//      is required unless it is optional and when it is not
+//      satisfied the field must not be present.
+func (f *InputField) jsonSchemaConditions() []interface{} {
+
+	rules := []interface{}{}
+	for _, c := range f.postFieldConditions {
+
+		rule := map[string]interface{}{
+			"if": c.expr.jsonSchema(),
+			"else": map[string]interface{}{
+				"not": map[string]interface{}{"required": []string{f.name}},
+			},
+		}
+		if !f.Optional() && f.groupId == 0 {
+			rule["then"] = map[string]interface{}{"required": []string{f.name}}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// out: the default value as it would appear
+//      in a document validated by the schema
+func (f *InputField) jsonSchemaDefault() interface{} {
+
+	if f.listInput {
+		items, err := parseListValue(*f.defaultValue)
+		if err == nil {
+			values := make([]interface{}, 0, len(items))
+			for _, v := range items {
+				values = append(values, f.jsonSchemaValue(v))
+			}
+			return values
+		}
+	}
+	return f.jsonSchemaValue(*f.defaultValue)
+}
+
+// in: value - a string value of the field
+// out: the value as it would appear in a document
+//      validated by the schema
+func (f *InputField) jsonSchemaValue(value string) interface{} {
+
+	if f.inputType == Number {
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+	return value
+}
@@ -0,0 +1,190 @@
+package forms_test
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goutils/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	test_data "github.com/mevansam/goforms/test/data"
+)
+
+var _ = Describe("Input Form JSON Schema", func() {
+
+	var (
+		err error
+		ig  *forms.InputGroup
+	)
+
+	BeforeEach(func() {
+		ig = test_data.NewTestInputCollection().Group("input-form")
+	})
+
+	It("generates a schema for the form's fields", func() {
+
+		var (
+			buf    bytes.Buffer
+			schema map[string]interface{}
+		)
+
+		err = ig.WriteJSONSchema(&buf)
+		Expect(err).NotTo(HaveOccurred())
+		err = json.Unmarshal(buf.Bytes(), &schema)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(schema["$schema"]).To(Equal("https://json-schema.org/draft/2020-12/schema"))
+		Expect(schema["title"]).To(Equal("input-form"))
+		Expect(schema["type"]).To(Equal("object"))
+
+		properties := schema["properties"].(map[string]interface{})
+		Expect(len(properties)).To(Equal(len(ig.InputFields())))
+		Expect(utils.MustGetValueAtPath("attrib14/default", properties)).To(Equal("default value for attrib14"))
+		Expect(utils.MustGetValueAtPath("attrib11/title", properties)).To(Equal("Attrib 11"))
+
+		// group1 is a root container of mutually
+		// exclusive fields and attrib14 has a default
+		Expect(schema["required"]).To(BeNil())
+		rules := schema["allOf"].([]interface{})
+		Expect(rules[0]).To(Equal(map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"required": []interface{}{"attrib11"}},
+				map[string]interface{}{"required": []interface{}{"attrib12"}},
+				map[string]interface{}{"required": []interface{}{"attrib13"}},
+			},
+		}))
+
+		// attrib121 is enabled only for specific values of attrib12
+		Expect(rules).To(ContainElement(map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{
+					"attrib12": map[string]interface{}{
+						"enum": []interface{}{"value for attrib12", "value for attrib12 - A"},
+					},
+				},
+				"required": []interface{}{"attrib12"},
+			},
+			"else": map[string]interface{}{
+				"not": map[string]interface{}{"required": []interface{}{"attrib121"}},
+			},
+		}))
+
+		// attrib1311 is required when attrib131 is provided
+		Expect(rules).To(ContainElement(map[string]interface{}{
+			"if":   map[string]interface{}{"required": []interface{}{"attrib131"}},
+			"then": map[string]interface{}{"required": []interface{}{"attrib1311"}},
+		}))
+	})
+
+	It("maps field validation attributes to schema keywords", func() {
+
+		g := forms.NewInputCollection().NewGroup("server", "server configuration")
+		_, err = g.NewInputField(forms.FieldAttributes{
+			Name:                       "port",
+			DisplayName:                "Port",
+			InputType:                  forms.Number,
+			AcceptedValues:             []string{"80", "443"},
+			AcceptedValuesErrorMessage: "invalid port",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = g.NewInputField(forms.FieldAttributes{
+			Name:            "password",
+			DisplayName:     "Password",
+			InputType:       forms.String,
+			Sensitive:       true,
+			InclusionFilter: "^.{8,}$",
+			ExclusionFilter: "password",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		schema := g.JSONSchema()
+		Expect(schema["required"]).To(Equal([]string{"port", "password"}))
+
+		properties := schema["properties"].(map[string]interface{})
+		Expect(properties["port"]).To(Equal(map[string]interface{}{
+			"type":  "number",
+			"title": "Port",
+			"enum":  []interface{}{float64(80), float64(443)},
+		}))
+		Expect(properties["password"]).To(Equal(map[string]interface{}{
+			"type":      "string",
+			"title":     "Password",
+			"pattern":   "^.{8,}$",
+			"not":       map[string]interface{}{"pattern": "password"},
+			"writeOnly": true,
+		}))
+	})
+
+	It("maps filters and comparisons only to values they apply to", func() {
+
+		g := forms.NewInputCollection().NewGroup("server", "server configuration")
+		for _, attributes := range []forms.FieldAttributes{
+			{
+				Name:            "port",
+				DisplayName:     "Port",
+				InputType:       forms.Number,
+				InclusionFilter: "^[0-9]+$",
+				ExclusionFilter: "^0",
+			},
+			{
+				Name:        "host",
+				DisplayName: "Host",
+			},
+			{
+				Name:        "tls",
+				DisplayName: "TLS",
+				DependsOn:   []string{`port =~ "443$"`},
+			},
+			{
+				Name:        "proxy",
+				DisplayName: "Proxy",
+				DependsOn:   []string{"host < 10", "port >= 1024"},
+			},
+		} {
+			_, err = g.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		schema := g.JSONSchema()
+		properties := schema["properties"].(map[string]interface{})
+		Expect(properties["port"]).To(Equal(map[string]interface{}{
+			"type":  "number",
+			"title": "Port",
+		}))
+
+		rules := schema["allOf"].([]interface{})
+		Expect(rules).To(ContainElement(map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"port": map[string]interface{}{}},
+				"required":   []string{"port"},
+			},
+			"then": map[string]interface{}{"required": []string{"tls"}},
+			"else": map[string]interface{}{
+				"not": map[string]interface{}{"required": []string{"tls"}},
+			},
+		}))
+		Expect(rules).To(ContainElement(map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"host": map[string]interface{}{}},
+				"required":   []string{"host"},
+			},
+			"then": map[string]interface{}{"required": []string{"proxy"}},
+			"else": map[string]interface{}{
+				"not": map[string]interface{}{"required": []string{"proxy"}},
+			},
+		}))
+		Expect(rules).To(ContainElement(map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"port": map[string]interface{}{"minimum": float64(1024)}},
+				"required":   []string{"port"},
+			},
+			"then": map[string]interface{}{"required": []string{"proxy"}},
+			"else": map[string]interface{}{
+				"not": map[string]interface{}{"required": []string{"proxy"}},
+			},
+		}))
+	})
+})
@@ -0,0 +1,167 @@
+package forms
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mevansam/goutils/logger"
+)
+
+// Types of sources a field's value can come from
+type ValueSourceType int
+
+const (
+	NoSource ValueSourceType = iota
+	BoundSource
+	DefaultSource
+	EnvSource
+	FileSource
+	HintSource
+)
+
+// The source of a field's value
+type ValueSource struct {
+	Type ValueSourceType
+
+	// name of the environment variable or the
+	// path of the file the value was read from
+	Name string
+}
+
+// out: the source as "bound", "default", "env:NAME",
+//      "file:PATH" or "hint". a value without a source
+//      is an empty string.
+func (s ValueSource) String() string {
+
+	switch s.Type {
+	case BoundSource:
+		return "bound"
+	case DefaultSource:
+		return "default"
+	case EnvSource:
+		return "env:" + s.Name
+	case FileSource:
+		return "file:" + s.Name
+	case HintSource:
+		return "hint"
+	}
+	return ""
+}
+
+// out: the source of the value returned by Value()
+func (f *InputField) ValueSource() ValueSource {
+
+	f.igMx.RLock()
+	defer f.igMx.RUnlock()
+
+	_, source, _ := f.sourcedValue()
+	return source
+}
+
+// out: the sources of the values returned by InputValues().
+//      the values of items of repeatable groups have their
+//      sources returned by the items' groups.
+func (g *InputGroup) InputValueSources() map[string]ValueSource {
+
+	g.igMx.RLock()
+	defer g.igMx.RUnlock()
+
+	sources := make(map[string]ValueSource)
+	for _, f := range g.inputFields(make(map[string]bool)) {
+		if f.inputSet {
+			_, sources[f.Name()], _ = f.sourcedValue()
+		}
+	}
+	return sources
+}
+
+// out: the value of the field and where it came from
+// out: an error if the value could not be retrieved
+//      from the form's secret store
+func (f *InputField) sourcedValue() (*string, ValueSource, error) {
+
+	var (
+		err    error
+		value  *string
+		source ValueSource
+	)
+
+	value = f.valueDeref()
+	source = f.source
+	if ref := f.secretRef(); ref != nil {
+		// values held in the secret store
+		// are retrieved only when requested
+		if value, err = f.resolveSecret(*ref); err != nil {
+			return nil, ValueSource{}, err
+		}
+	}
+	if value == nil {
+		source = ValueSource{}
+
+		if !f.valueFromFile {
+			// extract value from environment
+			for _, e := range f.envVars {
+				if envVal, exists := os.LookupEnv(e); exists {
+
+					logger.TraceMessage(
+						"Value of input field '%s' has been sourced from the environment variable '%s'.",
+						f.name, e)
+
+					value = &envVal
+					source = ValueSource{Type: EnvSource, Name: e}
+					break
+				}
+			}
+		}
+	}
+	if value != nil && f.listInput {
+		value = canonicalListValue(*value)
+	}
+	return value, source, nil
+}
+
+// sets the value of a field to one of the values of
+// its hints. the value is reported as sourced from the
+// field's hints.
+//
+// in: name  - the name of the field
+// in: value - one of the values returned by GetFieldValueHints()
+func (g *InputGroup) SetFieldValueFromHint(name, value string) error {
+
+	var (
+		err   error
+		field *InputField
+		hints []string
+	)
+
+	if field, err = g.GetInputField(name); err != nil {
+		return err
+	}
+	if hints, err = g.GetFieldValueHints(name); err != nil && len(hints) == 0 {
+		return err
+	}
+	for _, hint := range hints {
+		if hint == value {
+			return field.setValue(&value, bindHint)
+		}
+	}
+	return fmt.Errorf("'%s' is not one of the hint values of field '%s'", value, name)
+}
+
+// in: value - a value being set
+// in: path  - path of the file the value was read from
+//             or an empty string if not read from a file
+// in: mode  - how the value is being bound
+// out: the source of the value
+func sourceOf(value *string, path string, mode bindMode) ValueSource {
+
+	switch {
+	case value == nil:
+		return ValueSource{}
+	case len(path) > 0:
+		return ValueSource{Type: FileSource, Name: path}
+	case mode == bindHint:
+		return ValueSource{Type: HintSource}
+	}
+	return ValueSource{Type: BoundSource}
+}
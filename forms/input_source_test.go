@@ -0,0 +1,166 @@
+package forms_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goutils/utils"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Input Value Sources", func() {
+
+	var (
+		err error
+
+		ig     *forms.InputGroup
+		tmpDir string
+	)
+
+	source := func(name string) string {
+		field, err := ig.GetInputField(name)
+		Expect(err).NotTo(HaveOccurred())
+		return field.ValueSource().String()
+	}
+
+	BeforeEach(func() {
+		tmpDir, err = os.MkdirTemp("", "goforms")
+		Expect(err).NotTo(HaveOccurred())
+
+		ig = forms.NewInputCollection().NewGroup("server", "server settings")
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "host", DisplayName: "Host", EnvVars: []string{"TEST_SOURCE_HOST1", "TEST_SOURCE_HOST2"}},
+			{Name: "port", DisplayName: "Port", DefaultValue: utils.PtrToStr("8080")},
+			{Name: "user", DisplayName: "User"},
+			{Name: "cert", DisplayName: "Certificate", ValueFromFile: true},
+		} {
+			_, err = ig.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(ig.BindFields(&struct {
+			Host string  `form_field:"host"`
+			Port string  `form_field:"port"`
+			User string  `form_field:"user"`
+			Cert *string `form_field:"cert"`
+		}{
+			User: "gopher",
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+		os.Unsetenv("TEST_SOURCE_HOST2")
+	})
+
+	It("reports where each value came from", func() {
+
+		Expect(source("host")).To(BeEmpty())
+		Expect(source("port")).To(Equal("default"))
+		Expect(source("user")).To(Equal("bound"))
+		Expect(source("cert")).To(BeEmpty())
+
+		os.Setenv("TEST_SOURCE_HOST2", "localhost")
+		Expect(source("host")).To(Equal("env:TEST_SOURCE_HOST2"))
+
+		path := filepath.Join(tmpDir, "cert.pem")
+		Expect(os.WriteFile(path, []byte("certificate"), 0600)).To(Succeed())
+		Expect(ig.SetFieldValue("cert", path)).To(Succeed())
+		Expect(source("cert")).To(Equal("file:" + path))
+
+		Expect(ig.SetFieldValue("port", "9090")).To(Succeed())
+		Expect(source("port")).To(Equal("bound"))
+		field, err := ig.GetInputField("port")
+		Expect(err).NotTo(HaveOccurred())
+		field.ClearInput()
+		Expect(source("port")).To(Equal("default"))
+	})
+
+	It("reports values chosen from hints", func() {
+
+		path := filepath.Join(tmpDir, "users")
+		Expect(os.WriteFile(path, []byte("alice\nbob\n"), 0600)).To(Succeed())
+		Expect(ig.AddFieldValueHint("user", "file://"+path)).To(Succeed())
+
+		Expect(ig.SetFieldValueFromHint("user", "bob")).To(Succeed())
+		Expect(source("user")).To(Equal("hint"))
+		value, err := ig.GetFieldValue("user")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*value).To(Equal("bob"))
+
+		Expect(ig.SetFieldValueFromHint("user", "carol")).To(
+			MatchError("'carol' is not one of the hint values of field 'user'"))
+		Expect(source("user")).To(Equal("hint"))
+
+		// values which are set explicitly are bound
+		// even if they are the same as a hint value
+		Expect(ig.SetFieldValue("user", "alice")).To(Succeed())
+		Expect(source("user")).To(Equal("bound"))
+	})
+
+	It("restores the sources of values when changes are undone", func() {
+
+		path := filepath.Join(tmpDir, "users")
+		Expect(os.WriteFile(path, []byte("alice\nbob\n"), 0600)).To(Succeed())
+		Expect(ig.AddFieldValueHint("user", "file://"+path)).To(Succeed())
+		os.Setenv("TEST_SOURCE_HOST2", "localhost")
+
+		cursor := forms.NewInputCursor(ig).NextInput()
+		cursor, err = cursor.SetInputFromEnv("host")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source("host")).To(Equal("env:TEST_SOURCE_HOST2"))
+		cursor, err = cursor.NextInput().SetInput("port", "9090")
+		Expect(err).NotTo(HaveOccurred())
+		cursor, err = cursor.NextInput().SetInputFromHint("user", "bob")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source("user")).To(Equal("hint"))
+		cursor, err = cursor.SetInput("user", "carol")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source("user")).To(Equal("bound"))
+
+		cursor, err = cursor.Undo()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source("user")).To(Equal("hint"))
+		cursor, err = cursor.Undo()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source("user")).To(Equal("bound"))
+		cursor, err = cursor.Undo()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source("port")).To(Equal("default"))
+		cursor, err = cursor.SetInput("port", "9090")
+		Expect(err).NotTo(HaveOccurred())
+
+		// values from the environment are
+		// restored when changes are undone
+		cursor, err = cursor.PrevInput().SetInput("host", "example.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source("host")).To(Equal("bound"))
+		_, err = cursor.Undo()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(source("host")).To(Equal("env:TEST_SOURCE_HOST2"))
+	})
+
+	It("reports default values set explicitly as bound", func() {
+
+		Expect(source("port")).To(Equal("default"))
+		Expect(ig.SetFieldValue("port", "8080")).To(Succeed())
+		Expect(source("port")).To(Equal("bound"))
+	})
+
+	It("reports the sources of the input values", func() {
+
+		os.Setenv("TEST_SOURCE_HOST2", "localhost")
+		for _, f := range ig.InputFields() {
+			if f.Value() != nil {
+				f.SetInput()
+			}
+		}
+		Expect(ig.InputValueSources()).To(Equal(map[string]forms.ValueSource{
+			"host": {Type: forms.EnvSource, Name: "TEST_SOURCE_HOST2"},
+			"port": {Type: forms.DefaultSource},
+			"user": {Type: forms.BoundSource},
+		}))
+	})
+})
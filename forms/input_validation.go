@@ -0,0 +1,254 @@
+package forms
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// This error is returned when a value being set
+// on a field does not conform to the field's
+// input type.
+type InputTypeError struct {
+	// name of the field the value was set on
+	Field string
+	// input type of the field
+	Type InputType
+	// the underlying validation failure
+	Err error
+}
+
+func (e *InputTypeError) Error() string {
+	return fmt.Sprintf(
+		"value for field '%s' is not a valid %s: %s",
+		e.Field, e.Type.String(), e.Err.Error())
+}
+
+func (e *InputTypeError) Unwrap() error {
+	return e.Err
+}
+
+// validators for each input type. types
+// without a validator accept any value.
+var inputTypeValidators = map[InputType]func(value string) error{
+	Number:       validateNumber,
+	FilePath:     validateFilePath,
+	HttpUrl:      validateHttpUrl,
+	EmailAddress: validateEmailAddress,
+	JsonInput:    validateJsonInput,
+}
+
+// out: a readable name for the input type
+func (t InputType) String() string {
+	switch t {
+	case String:
+		return "string"
+	case Number:
+		return "number"
+	case FilePath:
+		return "file path"
+	case HttpUrl:
+		return "http url"
+	case EmailAddress:
+		return "email address"
+	case JsonInput:
+		return "json document"
+	case Container:
+		return "container"
+	case Repeatable:
+		return "repeatable group"
+	default:
+		return fmt.Sprintf("input type %d", int(t))
+	}
+}
+
+// in: value - the value to validate against the input type
+// out: nil if the value is valid for the input type
+func (t InputType) Validate(value string) error {
+	if validate, exists := inputTypeValidators[t]; exists {
+		return validate(value)
+	}
+	return nil
+}
+
+func validateNumber(value string) error {
+	if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return fmt.Errorf("'%s' cannot be parsed as a number", value)
+	}
+	return nil
+}
+
+// a file path is valid if it exists or if it
+// can be created within an existing directory
+func validateFilePath(value string) error {
+
+	var (
+		err      error
+		fileInfo os.FileInfo
+	)
+
+	if len(value) == 0 {
+		return fmt.Errorf("path is empty")
+	}
+	if _, err = os.Stat(value); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	dir := filepath.Dir(filepath.Clean(value))
+	if fileInfo, err = os.Stat(dir); err != nil || !fileInfo.IsDir() {
+		return fmt.Errorf("neither the path nor its parent directory '%s' exist", dir)
+	}
+	return nil
+}
+
+func validateHttpUrl(value string) error {
+
+	var (
+		err error
+		u   *url.URL
+	)
+
+	if u, err = url.Parse(value); err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url scheme must be http or https")
+	}
+	if len(u.Host) == 0 {
+		return fmt.Errorf("url does not have a host")
+	}
+	return nil
+}
+
+func validateEmailAddress(value string) error {
+
+	var (
+		err     error
+		address *mail.Address
+	)
+
+	if address, err = mail.ParseAddress(value); err != nil {
+		return err
+	}
+	// only a bare address is accepted and
+	// not one with a display name such as
+	// "Name <name@example.com>"
+	if address.Address != value {
+		return fmt.Errorf("'%s' is not a bare email address", value)
+	}
+	return nil
+}
+
+func validateJsonInput(value string) error {
+	if !json.Valid([]byte(value)) {
+		return fmt.Errorf("value is not well-formed json")
+	}
+	return nil
+}
+
+// A form level validation rule which validates
+// the values of fields in relation to each other.
+// It returns violations keyed by the name of the
+// field whose value is invalid or nil if all the
+// values are valid.
+type FormValidator func(form InputForm) map[string]error
+
+// A form whose values can be validated as a whole.
+// Implementations of InputForm are not required to
+// support validation so it is asserted when needed.
+type ValidatingForm interface {
+	InputForm
+
+	AddValidator(validator FormValidator)
+	Validate() error
+}
+
+// This error is returned when the values of a
+// form violate one or more form validation rules
+type FormValidationError struct {
+	// violations keyed by field name
+	Violations map[string][]error
+
+	fieldOrder []string
+}
+
+func (e *FormValidationError) Error() string {
+
+	var (
+		out strings.Builder
+	)
+
+	out.WriteString("form validation failed: ")
+	for i, name := range e.Fields() {
+		if i > 0 {
+			out.WriteString("; ")
+		}
+		out.WriteString("field '")
+		out.WriteString(name)
+		out.WriteString("': ")
+		for j, err := range e.Violations[name] {
+			if j > 0 {
+				out.WriteString(", ")
+			}
+			out.WriteString(err.Error())
+		}
+	}
+	return out.String()
+}
+
+// out: names of fields with violations in form order
+func (e *FormValidationError) Fields() []string {
+	return e.fieldOrder
+}
+
+// in: validator - a form level validation rule
+func (g *InputGroup) AddValidator(validator FormValidator) {
+	g.validators = append(g.validators, validator)
+}
+
+// out: a FormValidationError with all violations of the
+//      form's validation rules or nil if there are none
+func (g *InputGroup) Validate() error {
+
+	violations := make(map[string][]error)
+	for _, validator := range g.validators {
+		for name, err := range validator(g) {
+			if err != nil {
+				violations[name] = append(violations[name], err)
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	// order fields with violations in form order
+	// followed by any names not found in the form
+	fieldOrder := make([]string, 0, len(violations))
+	added := make(map[string]bool)
+	for _, f := range g.InputFields() {
+		if _, exists := violations[f.name]; exists {
+			fieldOrder = append(fieldOrder, f.name)
+			added[f.name] = true
+		}
+	}
+	unknown := []string{}
+	for name := range violations {
+		if !added[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+
+	return &FormValidationError{
+		Violations: violations,
+		fieldOrder: append(fieldOrder, unknown...),
+	}
+}
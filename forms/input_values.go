@@ -0,0 +1,258 @@
+package forms
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/mevansam/goutils/crypto"
+	"github.com/mevansam/goutils/logger"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Persisted form values. Values of sensitive
+// fields are encrypted with a key derived from
+// a passphrase and the salt saved with them. the
+// values of the items of repeatable groups are
+// saved as a list of documents per group.
+type valuesDocument struct {
+	Salt      []byte                      `json:"salt,omitempty"`
+	Values    map[string]string           `json:"values,omitempty"`
+	Encrypted map[string]string           `json:"encrypted,omitempty"`
+	Items     map[string][]valuesDocument `json:"items,omitempty"`
+}
+
+// in: path       - path of the file to save the form's bound values to
+// in: passphrase - passphrase used to encrypt values of sensitive fields
+func (g *InputGroup) SaveValues(path, passphrase string) error {
+
+	var (
+		err error
+
+		doc   valuesDocument
+		crypt *crypto.Crypt
+		data  []byte
+	)
+
+	// the key is only derived once a
+	// sensitive value needs to be saved
+	encrypt := func(name, value string) (string, error) {
+		if crypt == nil {
+			if len(passphrase) == 0 {
+				return "", fmt.Errorf(
+					"a passphrase is required to save the value of sensitive field '%s'",
+					name)
+			}
+			if doc.Salt, err = newSalt(); err != nil {
+				return "", err
+			}
+			if crypt, err = newPassphraseCrypt(passphrase, doc.Salt); err != nil {
+				return "", err
+			}
+		}
+		return crypt.EncryptB64(value)
+	}
+
+	g.igMx.RLock()
+	err = g.saveValues(&doc, encrypt)
+	g.igMx.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if data, err = json.MarshalIndent(&doc, "", "  "); err != nil {
+		return err
+	}
+	if err = os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+
+	logger.TraceMessage(
+		"Saved values of form '%s' to file '%s'.",
+		g.name, path)
+
+	return nil
+}
+
+// in: doc     - the document to add the group's bound values to
+// in: encrypt - encrypts the value of a sensitive field
+func (g *InputGroup) saveValues(
+	doc *valuesDocument,
+	encrypt func(name, value string) (string, error),
+) error {
+
+	var (
+		err error
+	)
+
+	doc.Values = make(map[string]string)
+	doc.Encrypted = make(map[string]string)
+
+	for _, f := range g.inputFields(make(map[string]bool)) {
+		if value := f.valueDeref(); value != nil {
+
+			if !f.sensitive {
+				doc.Values[f.name] = *value
+			} else if doc.Encrypted[f.name], err = encrypt(f.name, *value); err != nil {
+				return err
+			}
+		}
+	}
+	for name, i := range g.fieldNameSet {
+		if rg, ok := i.(*RepeatableGroup); ok && len(rg.items) > 0 {
+			if doc.Items == nil {
+				doc.Items = make(map[string][]valuesDocument)
+			}
+			items := make([]valuesDocument, len(rg.items))
+			for j, item := range rg.items {
+				if err = item.saveValues(&items[j], encrypt); err != nil {
+					return err
+				}
+			}
+			doc.Items[name] = items
+		}
+	}
+	return nil
+}
+
+// in: path       - path of the file to load the form's values from
+// in: passphrase - passphrase used to decrypt values of sensitive fields
+func (g *InputGroup) LoadValues(path, passphrase string) error {
+
+	var (
+		err error
+
+		doc   valuesDocument
+		crypt *crypto.Crypt
+		data  []byte
+	)
+
+	if data, err = os.ReadFile(path); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("error parsing values file '%s': %s", path, err.Error())
+	}
+
+	// the key is only derived once an
+	// encrypted value needs to be loaded
+	decrypt := func(name, cipherText string) (string, error) {
+		if crypt == nil {
+			if len(passphrase) == 0 {
+				return "", fmt.Errorf(
+					"a passphrase is required to load the encrypted values in '%s'",
+					path)
+			}
+			if crypt, err = newPassphraseCrypt(passphrase, doc.Salt); err != nil {
+				return "", err
+			}
+		}
+		value, err := crypt.DecryptB64(cipherText)
+		if err != nil {
+			return "", fmt.Errorf("unable to decrypt value of field '%s'", name)
+		}
+		return value, nil
+	}
+	return g.loadValues(&doc, decrypt)
+}
+
+// in: doc     - the document with the group's saved values
+// in: decrypt - decrypts the value of a sensitive field
+func (g *InputGroup) loadValues(
+	doc *valuesDocument,
+	decrypt func(name, cipherText string) (string, error),
+) error {
+
+	var (
+		err error
+
+		field *InputField
+		rg    *RepeatableGroup
+		items []*InputGroup
+	)
+
+	values := make(map[string]string)
+	for name, value := range doc.Values {
+		values[name] = value
+	}
+	for name, cipherText := range doc.Encrypted {
+		if values[name], err = decrypt(name, cipherText); err != nil {
+			return err
+		}
+	}
+
+	// values are set in name order so that any
+	// errors are reported deterministically
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if field, err = g.GetInputField(name); err != nil {
+			return err
+		}
+		// the saved value of a field whose value is read
+		// from a file is the content and not the path. the
+		// saved values of sensitive fields may be references
+		// to the form's secret store.
+		value := values[name]
+		if err = field.setValue(&value, bindSaved); err != nil {
+			return err
+		}
+		field.SetInput()
+	}
+
+	names = make([]string, 0, len(doc.Items))
+	for name := range doc.Items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if rg, err = g.GetRepeatableGroup(name); err != nil {
+			return err
+		}
+		// items are added to the group
+		// until there is one per document
+		for items = rg.Items(); len(items) < len(doc.Items[name]); items = rg.Items() {
+			if _, err = rg.AddItem(); err != nil {
+				return err
+			}
+		}
+		for j := range doc.Items[name] {
+			if err = items[j].loadValues(&doc.Items[name][j], decrypt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// out: a random salt for deriving a key from a passphrase
+func newSalt() ([]byte, error) {
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// in: passphrase - passphrase to derive the encryption key from
+// in: salt       - random salt saved with the encrypted data
+// out: a crypt using a key derived from the passphrase and salt
+func newPassphraseCrypt(passphrase string, salt []byte) (*crypto.Crypt, error) {
+
+	if len(salt) == 0 {
+		return nil, fmt.Errorf("a salt is required to derive an encryption key")
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.NewCrypt(key)
+}
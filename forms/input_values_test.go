@@ -0,0 +1,158 @@
+package forms_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/mevansam/goforms/forms"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Input Values", func() {
+
+	var (
+		err error
+
+		ig      *forms.InputGroup
+		tmpDir  string
+		binding map[string]*string
+	)
+
+	newForm := func() *forms.InputGroup {
+
+		g := forms.NewInputCollection().NewGroup("login", "login details")
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "user", DisplayName: "User"},
+			{Name: "password", DisplayName: "Password", Sensitive: true},
+			{Name: "port", DisplayName: "Port", InputType: forms.Number},
+		} {
+			_, err = g.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		binding = make(map[string]*string)
+		for _, f := range g.InputFields() {
+			binding[f.Name()] = new(string)
+			err = f.SetValueRef(binding[f.Name()])
+			Expect(err).NotTo(HaveOccurred())
+		}
+		return g
+	}
+
+	BeforeEach(func() {
+		tmpDir, err = os.MkdirTemp("", "goforms")
+		Expect(err).NotTo(HaveOccurred())
+
+		ig = newForm()
+		Expect(ig.SetFieldValue("user", "gopher")).To(Succeed())
+		Expect(ig.SetFieldValue("password", "s3cr3t")).To(Succeed())
+		Expect(ig.SetFieldValue("port", "8080")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("saves values encrypting sensitive values and loads them", func() {
+
+		var (
+			saved map[string]interface{}
+		)
+
+		path := filepath.Join(tmpDir, "values.json")
+		err = ig.SaveValues(path, "passphrase")
+		Expect(err).NotTo(HaveOccurred())
+
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("s3cr3t"))
+		err = json.Unmarshal(data, &saved)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved["values"]).To(Equal(map[string]interface{}{"user": "gopher", "port": "8080"}))
+		Expect(saved["salt"]).NotTo(BeEmpty())
+
+		ig = newForm()
+		err = ig.LoadValues(path, "passphrase")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*binding["user"]).To(Equal("gopher"))
+		Expect(*binding["password"]).To(Equal("s3cr3t"))
+		Expect(*binding["port"]).To(Equal("8080"))
+		Expect(ig.InputValues()).To(Equal(map[string]string{
+			"user":     "gopher",
+			"password": "s3cr3t",
+			"port":     "8080",
+		}))
+	})
+
+	It("saves and loads the values of the items of repeatable groups", func() {
+
+		newAccountsForm := func() *forms.InputGroup {
+			g := newForm()
+			rg, err := g.NewRepeatableGroup("accounts", "Account", "additional accounts", 0, 0)
+			Expect(err).NotTo(HaveOccurred())
+			for _, attributes := range []forms.FieldAttributes{
+				{Name: "name", DisplayName: "Name"},
+				{Name: "key", DisplayName: "Key", Sensitive: true},
+			} {
+				_, err = rg.NewInputField(attributes)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			return g
+		}
+
+		ig = newAccountsForm()
+		Expect(ig.SetFieldValue("user", "gopher")).To(Succeed())
+		rg, err := ig.GetRepeatableGroup("accounts")
+		Expect(err).NotTo(HaveOccurred())
+		for _, account := range [][2]string{{"alice", "k3y1"}, {"bob", "k3y2"}} {
+			item, err := rg.AddItem()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(item.SetFieldValue("name", account[0])).To(Succeed())
+			Expect(item.SetFieldValue("key", account[1])).To(Succeed())
+		}
+
+		path := filepath.Join(tmpDir, "values.json")
+		Expect(ig.SaveValues(path, "passphrase")).To(Succeed())
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("k3y"))
+
+		loaded := newAccountsForm()
+		Expect(loaded.LoadValues(path, "passphrase")).To(Succeed())
+		rg, err = loaded.GetRepeatableGroup("accounts")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rg.Items()).To(HaveLen(2))
+		Expect(loaded.InputValueMap()["accounts"]).To(Equal([]interface{}{
+			map[string]interface{}{"name": "alice", "key": "k3y1"},
+			map[string]interface{}{"name": "bob", "key": "k3y2"},
+		}))
+	})
+
+	It("fails to load sensitive values with the wrong passphrase", func() {
+
+		path := filepath.Join(tmpDir, "values.json")
+		err = ig.SaveValues(path, "passphrase")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = newForm().LoadValues(path, "wrong")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("requires a passphrase to save sensitive values", func() {
+		err = ig.SaveValues(filepath.Join(tmpDir, "values.json"), "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("validates values as they are loaded", func() {
+
+		path := filepath.Join(tmpDir, "values.json")
+		err = os.WriteFile(path, []byte(`{"values": {"port": "abc"}}`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = newForm().LoadValues(path, "")
+		Expect(err).To(HaveOccurred())
+	})
+})
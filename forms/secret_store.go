@@ -0,0 +1,480 @@
+package forms
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mevansam/goutils/crypto"
+	"github.com/mevansam/goutils/logger"
+)
+
+// Prefix of the references to values held
+// in a form's secret store. the reference is
+// bound to a sensitive field in place of its
+// value and is what InputValues() returns.
+const SecretRefPrefix = "secret://"
+
+// A store for the values of sensitive fields
+type SecretStore interface {
+
+	// in: value - the secret value to store
+	// out: the key with which the value can be retrieved
+	Put(value string) (string, error)
+
+	// in: key - the key returned when the value was stored
+	// out: the secret value
+	Get(key string) (string, error)
+
+	// in: key - the key of the value to remove
+	Delete(key string) error
+}
+
+// The secret store of a form which is
+// shared by all the inputs of the form
+type secrets struct {
+	store SecretStore
+}
+
+// sets the store the values of the form's sensitive
+// fields are kept in. values set after the store has
+// been set are bound to their fields as references to
+// the store and are retrieved only when requested.
+//
+// in: store - the secret store. if nil values of
+//             sensitive fields are bound as is.
+// out: an error if a sensitive field of the form is
+//      bound to a typed value
+func (g *InputGroup) SetSecretStore(store SecretStore) error {
+
+	var (
+		err error
+
+		key   string
+		plain []plainSecret
+	)
+
+	g.igMx.Lock()
+	prevStore := g.secrets.store
+	g.secrets.store = store
+	if err = g.checkSecretBindings(); err != nil {
+		g.secrets.store = prevStore
+		g.igMx.Unlock()
+		return err
+	}
+	if store != nil {
+		plain = g.plainSecrets(plain)
+	}
+	g.igMx.Unlock()
+
+	// sensitive values bound before the store was set
+	// are moved to the store. the store is accessed
+	// without the form being locked so the values are
+	// bound to the references only if they have not
+	// changed since the store was set.
+	for _, p := range plain {
+		if key, err = store.Put(p.value); err != nil {
+			return fmt.Errorf(
+				"unable to save value of field '%s' to secret store: %s",
+				p.field.name, err.Error())
+		}
+		ref := SecretRefPrefix + key
+
+		g.igMx.Lock()
+		bound := g.secrets.store == store && p.field.secretRef() == nil
+		if value := p.field.valueDeref(); bound && value != nil && *value == p.value {
+			err = p.field.bindValue(&ref, p.field.source, false)
+		} else {
+			bound = false
+		}
+		g.igMx.Unlock()
+
+		if !bound || err != nil {
+			p.field.releaseSecret(store, ref)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// A sensitive value bound to a field as is
+type plainSecret struct {
+	field *InputField
+	value string
+}
+
+// in: plain - the sensitive values collected so far
+// out: the sensitive values bound as is to the fields of
+//      the group and the items of its repeatable groups
+func (g *InputGroup) plainSecrets(plain []plainSecret) []plainSecret {
+
+	for _, i := range g.fieldNameSet {
+		switch input := i.(type) {
+
+		case *InputField:
+			if input.storesSecret() && input.valueCodec == nil && input.secretRef() == nil {
+				if value := input.valueDeref(); value != nil {
+					plain = append(plain, plainSecret{field: input, value: *value})
+				}
+			}
+
+		case *RepeatableGroup:
+			for _, item := range input.items {
+				plain = item.plainSecrets(plain)
+			}
+		}
+	}
+	return plain
+}
+
+// out: an error if a sensitive field of the group or the
+//      items of its repeatable groups cannot keep its
+//      value in the form's secret store
+func (g *InputGroup) checkSecretBindings() error {
+
+	for _, i := range g.fieldNameSet {
+		switch input := i.(type) {
+
+		case *InputField:
+			if err := input.checkSecretBinding(input.valueCodec); err != nil {
+				return err
+			}
+
+		case *RepeatableGroup:
+			for _, item := range input.items {
+				if err := item.checkSecretBindings(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// out: the store the values of the form's sensitive fields are kept in
+func (g *InputGroup) SecretStore() SecretStore {
+	g.igMx.RLock()
+	defer g.igMx.RUnlock()
+	return g.secrets.store
+}
+
+// out: whether values of the field are kept in the form's secret store
+func (f *InputField) storesSecret() bool {
+	return f.sensitive && f.secrets != nil && f.secrets.store != nil
+}
+
+// out: an error if the field is a sensitive field bound to
+//      a typed value which cannot hold a reference to a
+//      value in the form's secret store
+func (f *InputField) checkSecretBinding(codec *valueCodec) error {
+
+	if codec != nil && f.storesSecret() {
+		return fmt.Errorf(
+			"the sensitive field '%s' is bound to a typed value which cannot hold a reference to the secret store",
+			f.name)
+	}
+	return nil
+}
+
+// out: the reference to the field's value in the
+//      form's secret store or nil if its value is
+//      not held in the store
+func (f *InputField) secretRef() *string {
+
+	if f.storesSecret() {
+		if value := f.valueDeref(); value != nil && strings.HasPrefix(*value, SecretRefPrefix) {
+			return value
+		}
+	}
+	return nil
+}
+
+// out: the value of the field to return with the form's
+//      values which is the reference to the value if
+//      it is held in the form's secret store
+func (f *InputField) inputValue() *string {
+
+	if ref := f.secretRef(); ref != nil {
+		return ref
+	}
+	return f.value()
+}
+
+// in: ref - reference to a value in the form's secret store
+// out: the value retrieved from the store
+func (f *InputField) resolveSecret(ref string) (*string, error) {
+
+	value, err := f.secrets.store.Get(strings.TrimPrefix(ref, SecretRefPrefix))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"unable to retrieve value of field '%s' from secret store: %s",
+			f.name, err.Error())
+	}
+	return &value, nil
+}
+
+// A value of a sensitive field which has been
+// stored in the form's secret store before it
+// is bound to the field
+type storedSecret struct {
+	store SecretStore
+
+	ref   *string // the reference to bind to the field
+	value *string // the value the reference refers to
+	put   bool    // whether the value was put in the store
+
+	prevRef, prevValue *string
+}
+
+// in: value - value of the field to store
+// in: mode  - how the value is being bound
+// out: the stored value or nil if the field's
+//      values are not kept in a secret store
+func (f *InputField) storeSecret(value *string, mode bindMode) (*storedSecret, error) {
+
+	var (
+		err error
+
+		key         string
+		isSecretRef bool
+	)
+
+	f.igMx.RLock()
+	if !f.storesSecret() {
+		f.igMx.RUnlock()
+		return nil, nil
+	}
+	secret := &storedSecret{
+		store:   f.secrets.store,
+		prevRef: copyValue(f.secretRef()),
+	}
+	if err = f.checkSecretBinding(f.valueCodec); err == nil && value != nil {
+		// references to values already in the secret
+		// store are bound as is only when loading saved
+		// values. any other value is validated and stored.
+		isSecretRef = mode == bindSaved && strings.HasPrefix(*value, SecretRefPrefix)
+		if !isSecretRef {
+			value, err = f.validated(*value)
+		}
+	}
+	f.igMx.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if secret.prevRef != nil {
+		// the replaced value is retrieved so that
+		// it can be provided to change listeners
+		if secret.prevValue, err = secret.get(f, *secret.prevRef); err != nil {
+			logger.DebugMessage(err.Error())
+		}
+	}
+	if isSecretRef {
+		if secret.value, err = secret.get(f, *value); err != nil {
+			return nil, err
+		}
+		secret.ref = value
+
+	} else if value != nil {
+		if key, err = secret.store.Put(*value); err != nil {
+			return nil, fmt.Errorf(
+				"unable to save value of field '%s' to secret store: %s",
+				f.name, err.Error())
+		}
+		ref := SecretRefPrefix + key
+		secret.ref = &ref
+		secret.value = value
+		secret.put = true
+	}
+	return secret, nil
+}
+
+// in: f   - the field the value belongs to
+// in: ref - reference to a value in the store
+// out: the value retrieved from the store
+func (s *storedSecret) get(f *InputField, ref string) (*string, error) {
+
+	value, err := s.store.Get(strings.TrimPrefix(ref, SecretRefPrefix))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"unable to retrieve value of field '%s' from secret store: %s",
+			f.name, err.Error())
+	}
+	return &value, nil
+}
+
+// in: f        - the field the value is being bound to
+// in: boundRef - the reference bound to the field
+// out: the value of the field before the stored value is bound
+func (s *storedSecret) previousValue(f *InputField, boundRef *string) *string {
+
+	if boundRef != nil && s.prevRef != nil && *boundRef == *s.prevRef {
+		return copyValue(s.prevValue)
+	}
+	return copyValue(f.value())
+}
+
+// removes the values from the store which are no
+// longer referred to once the value has been bound
+//
+// in: f        - the field the value was bound to
+// in: boundRef - the reference bound to the field before
+// in: bindErr  - the error if the value could not be bound
+func (s *storedSecret) release(f *InputField, boundRef *string, bindErr error) {
+
+	if bindErr != nil {
+		// the stored value is not
+		// referred to by the field
+		if s.put {
+			f.releaseSecret(s.store, *s.ref)
+		}
+	} else if boundRef != nil && (s.ref == nil || *boundRef != *s.ref) {
+		f.releaseSecret(s.store, *boundRef)
+	}
+}
+
+// in: store - the secret store
+// in: ref   - reference to the value to remove from the store
+func (f *InputField) releaseSecret(store SecretStore, ref string) {
+
+	if err := store.Delete(strings.TrimPrefix(ref, SecretRefPrefix)); err != nil {
+		logger.DebugMessage(
+			"Unable to remove value of field '%s' from secret store: %s",
+			f.name, err.Error())
+	}
+}
+
+// A secret store which keeps secrets encrypted
+// in a local file. the key used to encrypt the
+// secrets is derived from a passphrase and the
+// salt saved with them.
+type FileSecretStore struct {
+	path  string
+	crypt *crypto.Crypt
+
+	doc  secretsDocument
+	lock sync.Mutex
+}
+
+// Persisted secrets of a FileSecretStore
+type secretsDocument struct {
+	Salt    []byte            `json:"salt"`
+	Secrets map[string]string `json:"secrets"`
+}
+
+// in: path       - path of the file the secrets are kept in.
+//                  it is created when the first secret is put.
+// in: passphrase - passphrase used to encrypt the secrets
+// out: a secret store backed by the file
+func NewFileSecretStore(path, passphrase string) (*FileSecretStore, error) {
+
+	var (
+		err error
+
+		data []byte
+	)
+
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("a passphrase is required to encrypt the secrets in '%s'", path)
+	}
+
+	s := &FileSecretStore{path: path}
+	if data, err = os.ReadFile(path); err == nil {
+		if err = json.Unmarshal(data, &s.doc); err != nil {
+			return nil, fmt.Errorf("error parsing secrets file '%s': %s", path, err.Error())
+		}
+	} else if os.IsNotExist(err) {
+		if s.doc.Salt, err = newSalt(); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, err
+	}
+	if s.doc.Secrets == nil {
+		s.doc.Secrets = make(map[string]string)
+	}
+
+	if s.crypt, err = newPassphraseCrypt(passphrase, s.doc.Salt); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileSecretStore) Put(value string) (string, error) {
+
+	var (
+		err error
+
+		id         [16]byte
+		cipherText string
+	)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, err = rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	if cipherText, err = s.crypt.EncryptB64(value); err != nil {
+		return "", err
+	}
+	key := hex.EncodeToString(id[:])
+	s.doc.Secrets[key] = cipherText
+	if err = s.save(); err != nil {
+		delete(s.doc.Secrets, key)
+		return "", err
+	}
+	return key, nil
+}
+
+func (s *FileSecretStore) Get(key string) (string, error) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	cipherText, exists := s.doc.Secrets[key]
+	if !exists {
+		return "", fmt.Errorf("secret '%s' does not exist", key)
+	}
+	value, err := s.crypt.DecryptB64(cipherText)
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt secret '%s'", key)
+	}
+	return value, nil
+}
+
+func (s *FileSecretStore) Delete(key string) error {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, exists := s.doc.Secrets[key]; !exists {
+		return nil
+	}
+	delete(s.doc.Secrets, key)
+	return s.save()
+}
+
+// saves the secrets to the store's file
+func (s *FileSecretStore) save() error {
+
+	data, err := json.MarshalIndent(&s.doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(s.path, data, 0600); err != nil {
+		return err
+	}
+
+	logger.TraceMessage(
+		"Saved %d secrets to file '%s'.",
+		len(s.doc.Secrets), s.path)
+
+	return nil
+}
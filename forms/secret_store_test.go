@@ -0,0 +1,225 @@
+package forms_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goforms/test/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Secret Store", func() {
+
+	var (
+		err error
+
+		ig      *forms.InputGroup
+		store   *mocks.FakeSecretStore
+		binding map[string]*string
+	)
+
+	BeforeEach(func() {
+		ig = forms.NewInputCollection().NewGroup("login", "login details")
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "user", DisplayName: "User"},
+			{Name: "password", DisplayName: "Password", Sensitive: true},
+		} {
+			_, err = ig.NewInputField(attributes)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		binding = make(map[string]*string)
+		for _, f := range ig.InputFields() {
+			binding[f.Name()] = new(string)
+			err = f.SetValueRef(binding[f.Name()])
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		store = mocks.NewFakeSecretStore()
+		Expect(ig.SetSecretStore(store)).To(Succeed())
+	})
+
+	It("binds references to sensitive values and resolves them lazily", func() {
+
+		Expect(ig.SetFieldValue("user", "gopher")).To(Succeed())
+		Expect(ig.SetFieldValue("password", "s3cr3t")).To(Succeed())
+
+		Expect(*binding["user"]).To(Equal("gopher"))
+		Expect(*binding["password"]).To(HavePrefix(forms.SecretRefPrefix))
+		Expect(store.Secrets).To(Equal(map[string]string{
+			strings.TrimPrefix(*binding["password"], forms.SecretRefPrefix): "s3cr3t",
+		}))
+
+		field, err := ig.GetInputField("password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*field.Value()).To(Equal("s3cr3t"))
+
+		for _, f := range ig.InputFields() {
+			f.SetInput()
+		}
+		Expect(ig.InputValues()).To(Equal(map[string]string{
+			"user":     "gopher",
+			"password": *binding["password"],
+		}))
+
+		// replaced and cleared values are
+		// removed from the store
+		Expect(ig.SetFieldValue("password", "n3w")).To(Succeed())
+		Expect(store.Secrets).To(HaveLen(1))
+		Expect(*field.Value()).To(Equal("n3w"))
+		field.ClearInput()
+		Expect(store.Secrets).To(BeEmpty())
+		Expect(field.Value()).To(BeNil())
+	})
+
+	It("validates values before they are stored", func() {
+
+		field, err := ig.GetInputField("password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(field.SetInclusionFilter(`^.{6,}$`, "too short")).To(Succeed())
+
+		err = ig.SetFieldValue("password", "abc")
+		Expect(err).To(MatchError("too short"))
+		Expect(store.Secrets).To(BeEmpty())
+	})
+
+	It("stores values that look like references", func() {
+
+		Expect(ig.SetFieldValue("password", forms.SecretRefPrefix+"forged")).To(Succeed())
+		Expect(*binding["password"]).To(HavePrefix(forms.SecretRefPrefix))
+		Expect(*binding["password"]).NotTo(Equal(forms.SecretRefPrefix + "forged"))
+
+		field, err := ig.GetInputField("password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*field.Value()).To(Equal(forms.SecretRefPrefix + "forged"))
+	})
+
+	It("returns errors retrieving values from the store", func() {
+
+		Expect(ig.SetFieldValue("password", "s3cr3t")).To(Succeed())
+		delete(store.Secrets, strings.TrimPrefix(*binding["password"], forms.SecretRefPrefix))
+
+		_, err = ig.GetFieldValue("password")
+		Expect(err).To(MatchError(ContainSubstring("unable to retrieve value of field 'password' from secret store")))
+	})
+
+	It("restores replaced secrets when changes are undone", func() {
+
+		Expect(ig.SetFieldValue("password", "0ld")).To(Succeed())
+
+		cursor := forms.NewInputCursor(ig).NextInput()
+		cursor, err = cursor.SetInput("user", "gopher")
+		Expect(err).NotTo(HaveOccurred())
+		cursor, err = cursor.NextInput().SetInput("password", "n3w")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Secrets).To(HaveLen(1))
+
+		_, err = cursor.Undo()
+		Expect(err).NotTo(HaveOccurred())
+		value, err := ig.GetFieldValue("password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*value).To(Equal("0ld"))
+	})
+
+	It("moves sensitive values bound before the store was set to the store", func() {
+
+		Expect(ig.SetSecretStore(nil)).To(Succeed())
+		Expect(ig.SetFieldValue("user", "gopher")).To(Succeed())
+		Expect(ig.SetFieldValue("password", "s3cr3t")).To(Succeed())
+		Expect(*binding["password"]).To(Equal("s3cr3t"))
+
+		Expect(ig.SetSecretStore(store)).To(Succeed())
+		Expect(*binding["user"]).To(Equal("gopher"))
+		Expect(*binding["password"]).To(HavePrefix(forms.SecretRefPrefix))
+		Expect(store.Secrets).To(Equal(map[string]string{
+			strings.TrimPrefix(*binding["password"], forms.SecretRefPrefix): "s3cr3t",
+		}))
+
+		field, err := ig.GetInputField("password")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*field.Value()).To(Equal("s3cr3t"))
+		Expect(field.ValueSource().Type).To(Equal(forms.BoundSource))
+
+		for _, f := range ig.InputFields() {
+			f.SetInput()
+		}
+		Expect(ig.InputValues()).To(Equal(map[string]string{
+			"user":     "gopher",
+			"password": *binding["password"],
+		}))
+	})
+
+	It("rejects sensitive fields bound to typed values", func() {
+
+		_, err = ig.NewInputField(forms.FieldAttributes{Name: "pin", DisplayName: "PIN", Sensitive: true})
+		Expect(err).NotTo(HaveOccurred())
+		field, err := ig.GetInputField("pin")
+		Expect(err).NotTo(HaveOccurred())
+
+		pin := 1234
+		Expect(field.SetValueRef(&pin)).To(MatchError(ContainSubstring("the sensitive field 'pin' is bound to a typed value")))
+		Expect(field.SetValueRef(new(string))).To(Succeed())
+
+		Expect(ig.SetSecretStore(nil)).To(Succeed())
+		Expect(field.SetValueRef(&pin)).To(Succeed())
+		Expect(ig.SetSecretStore(store)).To(MatchError(ContainSubstring("the sensitive field 'pin' is bound to a typed value")))
+		Expect(ig.SecretStore()).To(BeNil())
+	})
+
+	It("saves and loads references to the store", func() {
+
+		Expect(ig.SetFieldValue("password", "s3cr3t")).To(Succeed())
+		ref := *binding["password"]
+
+		tmpDir, err := os.MkdirTemp("", "goforms")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+
+		path := filepath.Join(tmpDir, "values.json")
+		Expect(ig.SaveValues(path, "passphrase")).To(Succeed())
+		*binding["password"] = ""
+		Expect(ig.LoadValues(path, "passphrase")).To(Succeed())
+
+		Expect(*binding["password"]).To(Equal(ref))
+		Expect(store.Secrets).To(HaveLen(1))
+
+		// references to secrets missing from
+		// the store cannot be loaded
+		store.Secrets = make(map[string]string)
+		Expect(ig.LoadValues(path, "passphrase")).To(MatchError(ContainSubstring("secret '1' does not exist")))
+	})
+
+	It("keeps secrets encrypted in a local file", func() {
+
+		tmpDir, err := os.MkdirTemp("", "goforms")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+
+		path := filepath.Join(tmpDir, "secrets.json")
+		fileStore, err := forms.NewFileSecretStore(path, "passphrase")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ig.SetSecretStore(fileStore)).To(Succeed())
+
+		Expect(ig.SetFieldValue("password", "s3cr3t")).To(Succeed())
+		data, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).NotTo(ContainSubstring("s3cr3t"))
+		Expect(string(data)).To(ContainSubstring(`"salt"`))
+
+		// the secret can be read by a store
+		// opened with the same passphrase
+		key := strings.TrimPrefix(*binding["password"], forms.SecretRefPrefix)
+		fileStore, err = forms.NewFileSecretStore(path, "passphrase")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fileStore.Get(key)).To(Equal("s3cr3t"))
+
+		fileStore, err = forms.NewFileSecretStore(path, "wrong")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = fileStore.Get(key)
+		Expect(err).To(HaveOccurred())
+	})
+})
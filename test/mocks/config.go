@@ -77,6 +77,14 @@ func (f *FakeConfig) GetInternalValue(name string) (*string, bool) {
 	return v.value, exists
 }
 
+func (f *FakeConfig) Name() string {
+	return f.inputGroup.Name()
+}
+
+func (f *FakeConfig) Description() string {
+	return f.inputGroup.Description()
+}
+
 func (f *FakeConfig) InputForm() (forms.InputForm, error) {
 	return f.inputGroup, nil
 }
@@ -86,7 +94,37 @@ func (f *FakeConfig) GetValue(name string) (*string, error) {
 }
 
 func (f *FakeConfig) Copy() (config.Configurable, error) {
-	return nil, nil
+
+	var (
+		err error
+
+		inputGroup *forms.InputGroup
+		field      *forms.InputField
+	)
+
+	if inputGroup, err = f.inputGroup.Clone(nil); err != nil {
+		return nil, err
+	}
+	c := &FakeConfig{
+		inputGroup: inputGroup,
+		values:     make(map[string]*valueRef),
+	}
+	for name, v := range f.values {
+		vc := valueRef{nil}
+		if v.value != nil {
+			value := *v.value
+			vc.value = &value
+		}
+		c.values[name] = &vc
+
+		if field, err = inputGroup.GetInputField(name); err != nil {
+			return nil, err
+		}
+		if err = field.SetValueRef(&vc.value); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
 }
 
 func (f *FakeConfig) IsValid() bool {
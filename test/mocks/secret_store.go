@@ -0,0 +1,49 @@
+package mocks
+
+import (
+	"fmt"
+	"sync"
+)
+
+// An in-memory secret store
+type FakeSecretStore struct {
+	Secrets map[string]string
+
+	lock   sync.Mutex
+	nextID int
+}
+
+func NewFakeSecretStore() *FakeSecretStore {
+	return &FakeSecretStore{
+		Secrets: make(map[string]string),
+	}
+}
+
+func (s *FakeSecretStore) Put(value string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.nextID++
+	key := fmt.Sprintf("%d", s.nextID)
+	s.Secrets[key] = value
+	return key, nil
+}
+
+func (s *FakeSecretStore) Get(key string) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	value, exists := s.Secrets[key]
+	if !exists {
+		return "", fmt.Errorf("secret '%s' does not exist", key)
+	}
+	return value, nil
+}
+
+func (s *FakeSecretStore) Delete(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.Secrets, key)
+	return nil
+}
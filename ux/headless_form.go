@@ -0,0 +1,465 @@
+package ux
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goutils/logger"
+)
+
+// A required input for which no
+// value could be determined
+type MissingInput struct {
+	// name of the input
+	Name string
+	// display name of the input
+	DisplayName string
+	// if the input is a container then
+	// these are names of the inputs any
+	// one of which needs to be provided
+	Alternatives []string
+}
+
+// This error is returned when values for
+// one or more required inputs could not
+// be determined
+type MissingInputsError struct {
+	Inputs []MissingInput
+}
+
+func (e *MissingInputsError) Error() string {
+
+	var (
+		out strings.Builder
+	)
+
+	out.WriteString("values for the following required inputs were not provided: ")
+	for i, input := range e.Inputs {
+		if i > 0 {
+			out.WriteString(", ")
+		}
+		out.WriteString(input.Name)
+		if len(input.Alternatives) > 0 {
+			out.WriteString(" (one of ")
+			out.WriteString(strings.Join(input.Alternatives, "|"))
+			out.WriteString(")")
+		}
+	}
+	return out.String()
+}
+
+// Collects form input without prompting
+// the user. Values for the form's inputs
+// are sourced in order from a map of
+// values, answer files, the environment
+// and the inputs' default values.
+type HeadlessForm struct {
+	inputGroup *forms.InputGroup
+
+	values map[string]string
+}
+
+func GetHeadlessFormInput(
+	inputForm forms.InputForm,
+	values map[string]string,
+	answerFile string,
+	tags ...string,
+) error {
+
+	var (
+		err error
+
+		headlessForm *HeadlessForm
+	)
+
+	if headlessForm, err = NewHeadlessForm(inputForm, values); err != nil {
+		return err
+	}
+	if len(answerFile) > 0 {
+		if err = headlessForm.AddAnswerFile(answerFile); err != nil {
+			return err
+		}
+	}
+	return headlessForm.GetInput(tags...)
+}
+
+func NewHeadlessForm(
+	input forms.Input,
+	values map[string]string,
+) (*HeadlessForm, error) {
+
+	var (
+		ok         bool
+		inputGroup *forms.InputGroup
+	)
+
+	if inputGroup, ok = input.(*forms.InputGroup); !ok {
+		return nil, fmt.Errorf("input is not of type forms.InputGroup: %#v", input)
+	}
+
+	hf := &HeadlessForm{
+		inputGroup: inputGroup,
+		values:     make(map[string]string),
+	}
+	for name, value := range values {
+		hf.values[name] = value
+	}
+	return hf, nil
+}
+
+// in: path - path to a YAML or JSON answer file with a map of
+//            input names to values. values already provided
+//            take precedence over values in the answer file.
+// out: an error if the file has answers for inputs
+//      that are not fields or repeatable groups of
+//      the form
+func (hf *HeadlessForm) AddAnswerFile(path string) error {
+
+	var (
+		err error
+
+		data    []byte
+		answers map[string]interface{}
+	)
+
+	if data, err = os.ReadFile(path); err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(data, &answers); err != nil {
+		return fmt.Errorf("error parsing answer file '%s': %s", path, err.Error())
+	}
+	if unknown := unknownInputs(hf.inputGroup, answers); len(unknown) > 0 {
+		return fmt.Errorf(
+			"answer file '%s' has answers for unknown inputs: %s",
+			path, strings.Join(unknown, ", "))
+	}
+
+	for name, answer := range answers {
+		if _, exists := hf.values[name]; exists || answer == nil {
+			continue
+		}
+		if hf.values[name], err = answerValue(answer); err != nil {
+			return fmt.Errorf(
+				"error converting answer for input '%s' in file '%s': %s",
+				name, path, err.Error())
+		}
+	}
+	return nil
+}
+
+// in: inputGroup - the group the answers are for
+// in: answers    - answers keyed by input name
+// out: the sorted names of the answers which are not
+//      for a field or repeatable group of the group
+func unknownInputs(inputGroup *forms.InputGroup, answers map[string]interface{}) []string {
+
+	unknown := []string{}
+	for name := range answers {
+		if _, err := inputGroup.GetInputField(name); err == nil {
+			continue
+		}
+		if _, err := inputGroup.GetRepeatableGroup(name); err == nil {
+			continue
+		}
+		unknown = append(unknown, name)
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// in: answer - an answer parsed from an answer file
+// out: the answer as an input value
+func answerValue(answer interface{}) (string, error) {
+
+	switch v := answer.(type) {
+	case string:
+		return v, nil
+	case map[string]interface{}, []interface{}:
+		// structured answers are provided
+		// to the input as json documents
+		data, err := json.Marshal(v)
+		return string(data), err
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+// in: tags - only inputs with these tags will be collected
+// out: a MissingInputsError if values for any required
+//      inputs could not be determined or a
+//      FormValidationError if the values collected
+//      violate any of the form's validation rules
+func (hf *HeadlessForm) GetInput(tags ...string) error {
+
+	var (
+		err error
+
+		cursor     *forms.InputCursor
+		input      forms.Input
+		inputField *forms.InputField
+	)
+
+	missing := []MissingInput{}
+	// dependents of inputs that are missing
+	// are not required so they are skipped
+	skip := make(map[string]bool)
+
+	cursor = forms.NewInputCursor(hf.inputGroup, tags...)
+	cursor = cursor.NextInput()
+
+	for cursor != nil {
+		if input, err = cursor.GetCurrentInput(); err != nil {
+			return err
+		}
+
+		inputField = nil
+		if skip[input.Name()] {
+			logger.TraceMessage(
+				"Skipping input '%s' as an input it depends on is missing.",
+				input.Name())
+
+		} else if input.Type() == forms.Repeatable {
+
+			var missingItemInputs []MissingInput
+			if missingItemInputs, err = hf.getItems(input.(*forms.RepeatableGroup), tags...); err != nil {
+				return err
+			}
+			missing = append(missing, missingItemInputs...)
+
+		} else if input.Type() == forms.Container {
+
+			inputs := input.EnabledInputs(true, tags...)
+			if len(inputs) > 0 {
+				if inputField = hf.selectInput(inputs); inputField == nil {
+					alternatives := make([]string, 0, len(inputs))
+					for _, i := range inputs {
+						alternatives = append(alternatives, i.Name())
+					}
+					missing = append(missing, MissingInput{
+						Name:         input.Name(),
+						DisplayName:  input.DisplayName(),
+						Alternatives: alternatives,
+					})
+				}
+			}
+
+		} else if input.Enabled(true, tags...) {
+			inputField = input.(*forms.InputField)
+			if !hf.hasValue(inputField) {
+				missing = append(missing, MissingInput{
+					Name:        input.Name(),
+					DisplayName: input.DisplayName(),
+				})
+				hf.skipDependents(input, skip)
+				inputField = nil
+			}
+		}
+
+		if inputField != nil {
+			if cursor, err = hf.setInput(cursor, inputField); err != nil {
+				return err
+			}
+		}
+		cursor = cursor.NextInput()
+	}
+
+	if len(missing) > 0 {
+		return &MissingInputsError{Inputs: missing}
+	}
+	return hf.inputGroup.Validate()
+}
+
+// in: rg   - the repeatable group to collect the items of
+// in: tags - only inputs with these tags will be collected
+// out: the required inputs of the group's items that are missing
+func (hf *HeadlessForm) getItems(
+	rg *forms.RepeatableGroup,
+	tags ...string,
+) ([]MissingInput, error) {
+
+	var (
+		err error
+
+		answers []interface{}
+		values  map[string]string
+
+		item       *forms.InputGroup
+		itemForm   *HeadlessForm
+		missingErr *MissingInputsError
+	)
+
+	missing := []MissingInput{}
+
+	value, exists := hf.values[rg.Name()]
+	if !exists {
+		// existing items such as those of a bound
+		// slice are kept if no items are provided
+		if len(rg.Items()) < rg.MinItems() {
+			missing = append(missing, MissingInput{
+				Name:        rg.Name(),
+				DisplayName: rg.DisplayName(),
+			})
+		}
+		return missing, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	if err = decoder.Decode(&answers); err != nil {
+		return nil, fmt.Errorf(
+			"value for '%s' must be a json array of objects: %s",
+			rg.Name(), err.Error())
+	}
+	if len(answers) < rg.MinItems() {
+		return nil, fmt.Errorf(
+			"at least %d item(s) are required for '%s' but %d were provided",
+			rg.MinItems(), rg.Name(), len(answers))
+	}
+
+	// provided items replace any existing items
+	for len(rg.Items()) > 0 {
+		if err = rg.RemoveItem(len(rg.Items()) - 1); err != nil {
+			return nil, err
+		}
+	}
+	for i, answer := range answers {
+		itemAnswers, ok := answer.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf(
+				"item %d of '%s' is not an object",
+				i+1, rg.Name())
+		}
+		if unknown := unknownInputs(&rg.InputGroup, itemAnswers); len(unknown) > 0 {
+			return nil, fmt.Errorf(
+				"item %d of '%s' has answers for unknown inputs: %s",
+				i+1, rg.Name(), strings.Join(unknown, ", "))
+		}
+		values = make(map[string]string)
+		for name, a := range itemAnswers {
+			if a == nil {
+				continue
+			}
+			if values[name], err = answerValue(a); err != nil {
+				return nil, err
+			}
+		}
+
+		if item, err = rg.AddItem(); err != nil {
+			return nil, err
+		}
+		if itemForm, err = NewHeadlessForm(item, values); err != nil {
+			return nil, err
+		}
+		if err = itemForm.GetInput(tags...); err != nil {
+			if !errors.As(err, &missingErr) {
+				return nil, fmt.Errorf(
+					"invalid value for item %d of '%s': %w",
+					i+1, rg.Name(), err)
+			}
+			for _, m := range missingErr.Inputs {
+				m.Name = fmt.Sprintf("%s[%d].%s", rg.Name(), i, m.Name)
+				missing = append(missing, m)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// in: inputs - enabled inputs of a container
+// out: the input to collect a value for in order of
+//      preference of explicitly provided values, values
+//      from the environment and existing or default values
+func (hf *HeadlessForm) selectInput(inputs []forms.Input) *forms.InputField {
+
+	for _, i := range inputs {
+		if _, exists := hf.values[i.Name()]; exists {
+			return i.(*forms.InputField)
+		}
+	}
+	for _, i := range inputs {
+		if _, exists := lookupEnv(i.(*forms.InputField)); exists {
+			return i.(*forms.InputField)
+		}
+	}
+	for _, i := range inputs {
+		if f := i.(*forms.InputField); f.Value() != nil || f.DefaultValue() != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// out: whether a value can be determined for the input
+func (hf *HeadlessForm) hasValue(inputField *forms.InputField) bool {
+
+	if _, exists := hf.values[inputField.Name()]; exists {
+		return true
+	}
+	if _, exists := lookupEnv(inputField); exists {
+		return true
+	}
+	return inputField.Value() != nil || inputField.DefaultValue() != nil
+}
+
+// in: cursor - cursor positioned at the input
+// in: inputField - the input field to set the value of
+// out: the cursor after the input has been set
+func (hf *HeadlessForm) setInput(
+	cursor *forms.InputCursor,
+	inputField *forms.InputField,
+) (*forms.InputCursor, error) {
+
+	var (
+		err error
+		c   *forms.InputCursor
+	)
+
+	name := inputField.Name()
+	if value, exists := hf.values[name]; exists {
+		c, err = cursor.SetInput(name, value)
+
+	} else if _, exists := lookupEnv(inputField); exists {
+		logger.TraceMessage(
+			"Input '%s' has been set from the environment.", name)
+		c, err = cursor.SetInputFromEnv(name)
+
+	} else if inputField.Value() != nil {
+		c, err = cursor.SetDefaultInput(name)
+
+	} else {
+		c, err = cursor.SetInput(name, *inputField.DefaultValue())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for input '%s': %w", name, err)
+	}
+	return c, nil
+}
+
+// in: input - input whose dependents should be skipped
+// in: skip  - set of names of inputs to skip
+func (hf *HeadlessForm) skipDependents(input forms.Input, skip map[string]bool) {
+
+	for _, i := range input.Inputs() {
+		skip[i.Name()] = true
+		hf.skipDependents(i, skip)
+	}
+}
+
+// in: inputField - input field whose environment variables to lookup
+// out: the value of the first environment variable that is set
+func lookupEnv(inputField *forms.InputField) (string, bool) {
+
+	for _, e := range inputField.EnvVars() {
+		if value, exists := os.LookupEnv(e); exists {
+			return value, true
+		}
+	}
+	return "", false
+}
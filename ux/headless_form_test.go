@@ -0,0 +1,207 @@
+package ux_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goforms/ux"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	test_data "github.com/mevansam/goforms/test/data"
+)
+
+var _ = Describe("Headless form input", func() {
+
+	var (
+		err error
+
+		inputGroup *forms.InputGroup
+	)
+
+	BeforeEach(func() {
+
+		inputGroup = test_data.NewTestInputCollection().Group("input-form")
+		for _, f := range inputGroup.InputFields() {
+			err = f.SetValueRef(new(string))
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+
+	It("collects input from a map of values and default values", func() {
+
+		err = ux.GetHeadlessFormInput(inputGroup, map[string]string{
+			"attrib12":   "value for attrib12 - A",
+			"attrib121":  "value for attrib121",
+			"attrib131":  "value for attrib131",
+			"attrib1311": "value for attrib1311",
+			"attrib1312": "value for attrib1312",
+		}, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inputGroup.InputValues()).To(Equal(map[string]string{
+			"attrib12":   "value for attrib12 - A",
+			"attrib121":  "value for attrib121",
+			"attrib131":  "value for attrib131",
+			"attrib1311": "value for attrib1311",
+			"attrib1312": "value for attrib1312",
+			"attrib14":   "default value for attrib14",
+		}))
+	})
+
+	It("collects input from an answer file and the environment", func() {
+
+		tmpDir, err := os.MkdirTemp("", "goforms")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+
+		answerFile := filepath.Join(tmpDir, "answers.yml")
+		err = os.WriteFile(answerFile, []byte(testAnswerFile), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		os.Setenv("ATTRIB13_ENV1", "value for attrib13 from env")
+		defer os.Unsetenv("ATTRIB13_ENV1")
+
+		hf, err := ux.NewHeadlessForm(inputGroup, map[string]string{
+			"attrib1312": "value for attrib1312 from map",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		err = hf.AddAnswerFile(answerFile)
+		Expect(err).NotTo(HaveOccurred())
+		err = hf.GetInput()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inputGroup.InputValues()).To(Equal(map[string]string{
+			"attrib13":   "value for attrib13 from env",
+			"attrib131":  "value for attrib131",
+			"attrib1311": "1311",
+			"attrib1312": "value for attrib1312 from map",
+			"attrib133":  "default value for attrib133",
+			"attrib14":   "value for attrib14 - X",
+			"attrib141":  `{"a":[1,2]}`,
+		}))
+
+		// values from the environment
+		// are not reported as bound
+		sources := inputGroup.InputValueSources()
+		Expect(sources["attrib13"]).To(Equal(forms.ValueSource{Type: forms.EnvSource, Name: "ATTRIB13_ENV1"}))
+		Expect(sources["attrib1312"]).To(Equal(forms.ValueSource{Type: forms.BoundSource}))
+	})
+
+	It("rejects answer files with answers for unknown inputs", func() {
+
+		tmpDir, err := os.MkdirTemp("", "goforms")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+
+		answerFile := filepath.Join(tmpDir, "answers.yml")
+		err = os.WriteFile(answerFile, []byte(testAnswerFile+"atrib12: typo\nattrib99: unknown\n"), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		hf, err := ux.NewHeadlessForm(inputGroup, map[string]string{})
+		Expect(err).NotTo(HaveOccurred())
+		err = hf.AddAnswerFile(answerFile)
+		Expect(err).To(MatchError(fmt.Sprintf(
+			"answer file '%s' has answers for unknown inputs: atrib12, attrib99", answerFile)))
+	})
+
+	It("returns all required inputs that are missing", func() {
+
+		var (
+			missingErr *ux.MissingInputsError
+		)
+
+		err = ux.GetHeadlessFormInput(inputGroup, map[string]string{
+			"attrib12": "value for attrib12 - B",
+		}, "", "tag1")
+		Expect(err).To(HaveOccurred())
+		Expect(errors.As(err, &missingErr)).To(BeTrue())
+
+		Expect(missingErr.Inputs).To(Equal([]ux.MissingInput{
+			{
+				Name:         "group2",
+				DisplayName:  "Group 2",
+				Alternatives: []string{"attrib122"},
+			},
+			{
+				Name:        "attrib131",
+				DisplayName: "Attrib 131",
+			},
+		}))
+	})
+
+	It("returns an error for invalid values", func() {
+
+		var (
+			missingErr *ux.MissingInputsError
+		)
+
+		field, err := inputGroup.GetInputField("attrib14")
+		Expect(err).NotTo(HaveOccurred())
+		field.SetAcceptedValues([]string{"a", "b"}, "attrib14 must be 'a' or 'b'")
+
+		err = ux.GetHeadlessFormInput(inputGroup, map[string]string{
+			"attrib11": "value for attrib11",
+			"attrib14": "c",
+		}, "")
+		Expect(err).To(HaveOccurred())
+		Expect(errors.As(err, &missingErr)).To(BeFalse())
+		Expect(err.Error()).To(Equal("invalid value for input 'attrib14': attrib14 must be 'a' or 'b'"))
+	})
+
+	It("collects the items of repeatable groups", func() {
+
+		var (
+			missingErr *ux.MissingInputsError
+		)
+
+		ig := forms.NewInputCollection().NewGroup("network", "network settings")
+		rg, err := ig.NewRepeatableGroup("interfaces", "Interface", "network interfaces", 1, 0)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rg.NewInputField(forms.FieldAttributes{Name: "name", DisplayName: "Name"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rg.NewInputField(forms.FieldAttributes{Name: "mtu", DisplayName: "MTU", InputType: forms.Number})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ux.GetHeadlessFormInput(ig, map[string]string{}, "")
+		Expect(errors.As(err, &missingErr)).To(BeTrue())
+		Expect(missingErr.Inputs).To(Equal([]ux.MissingInput{
+			{Name: "interfaces", DisplayName: "Interface"},
+		}))
+
+		err = ux.GetHeadlessFormInput(ig, map[string]string{
+			"interfaces": `[{"name":"eth0","mtu":1500},{"name":"eth1"}]`,
+		}, "")
+		Expect(errors.As(err, &missingErr)).To(BeTrue())
+		Expect(missingErr.Inputs).To(Equal([]ux.MissingInput{
+			{Name: "interfaces[1].mtu", DisplayName: "MTU"},
+		}))
+
+		err = ux.GetHeadlessFormInput(ig, map[string]string{
+			"interfaces": `[{"name":"eth0","mtu":1500},{"name":"eth1","mut":9000}]`,
+		}, "")
+		Expect(err).To(MatchError("item 2 of 'interfaces' has answers for unknown inputs: mut"))
+
+		err = ux.GetHeadlessFormInput(ig, map[string]string{
+			"interfaces": `[{"name":"eth0","mtu":1500},{"name":"eth1","mtu":9000}]`,
+		}, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(rg.Items())).To(Equal(2))
+		Expect(ig.InputValues()).To(Equal(map[string]string{
+			"interfaces": `[{"mtu":"1500","name":"eth0"},{"mtu":"9000","name":"eth1"}]`,
+		}))
+	})
+})
+
+const testAnswerFile = `
+attrib131: value for attrib131
+attrib1311: 1311
+attrib1312: value for attrib1312 from file
+attrib14: value for attrib14 - X
+attrib141:
+  a: [1, 2]
+`
@@ -0,0 +1,58 @@
+package ux
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/peterh/liner"
+)
+
+// A line editor which writes prompts to a writer
+// and reads the responses line by line from a
+// reader. like liner when its input is not a
+// terminal suggestions are not shown. responses
+// are echoed so that the writer receives a full
+// transcript of the input.
+type scriptedLineEditor struct {
+	reader *bufio.Reader
+	out    io.Writer
+}
+
+// in: in  - reader responses are read from
+// in: out - writer prompts and responses are written to
+func newScriptedLineEditor(in io.Reader, out io.Writer) *scriptedLineEditor {
+	return &scriptedLineEditor{
+		reader: bufio.NewReader(in),
+		out:    out,
+	}
+}
+
+func (l *scriptedLineEditor) Prompt(prompt string) (string, error) {
+
+	var (
+		err  error
+		line string
+	)
+
+	fmt.Fprint(l.out, prompt)
+	if line, err = l.reader.ReadString('\n'); err != nil && (err != io.EOF || len(line) == 0) {
+		fmt.Fprintln(l.out)
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	fmt.Fprintln(l.out, line)
+	return line, nil
+}
+
+func (l *scriptedLineEditor) PromptWithSuggestion(prompt, text string, pos int) (string, error) {
+	return l.Prompt(prompt)
+}
+
+func (l *scriptedLineEditor) SetCompleter(f liner.Completer) {
+}
+
+func (l *scriptedLineEditor) Close() error {
+	return nil
+}
@@ -1,7 +1,10 @@
 package ux
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -22,13 +25,73 @@ const (
 	DescOnly FieldShowOption = iota
 	DescAndValues
 	DescAndDefaults
+	// values are shown with the source
+	// they were retrieved from
+	DescValuesAndSources
 )
 
+// Response to a prompt that returns
+// to the previous input prompted for
+const BackResponse = ":back"
+
+// A line editor used by a TextForm to prompt for
+// input. it is implemented by liner's line editor.
+type LineEditor interface {
+	Prompt(prompt string) (string, error)
+	PromptWithSuggestion(prompt, text string, pos int) (string, error)
+	SetCompleter(f liner.Completer)
+	Close() error
+}
+
+// This error is returned when the
+// user aborts input of a form
+type InputAbortedError struct {
+	// name of the form
+	Form string
+	// error the prompt was aborted with
+	Err error
+}
+
+func (e *InputAbortedError) Error() string {
+	return fmt.Sprintf("input of form '%s' was aborted", e.Form)
+}
+
+func (e *InputAbortedError) Unwrap() error {
+	return e.Err
+}
+
 type TextForm struct {
 	title,
 	heading string
 
 	inputGroup *forms.InputGroup
+
+	in  io.Reader
+	out io.Writer
+
+	lineEditor LineEditor
+}
+
+// An option of a TextForm
+type TextFormOption func(tf *TextForm)
+
+// in: in         - reader responses to prompts are read from
+// in: out        - writer the form and its prompts are written to
+// in: lineEditor - line editor to prompt with. if nil then a line
+//                  editor which writes prompts to out and reads
+//                  responses line by line from in is used.
+// out: an option which sets the form's input and output
+func WithIO(
+	in io.Reader,
+	out io.Writer,
+	lineEditor LineEditor,
+) TextFormOption {
+
+	return func(tf *TextForm) {
+		tf.in = in
+		tf.out = out
+		tf.lineEditor = lineEditor
+	}
 }
 
 func GetFormInput(
@@ -57,14 +120,13 @@ func GetFormInput(
 		tags...,
 	); err != nil {
 
-		if err == liner.ErrPromptAborted {
+		var abortedErr *InputAbortedError
+		if errors.As(err, &abortedErr) {
 			fmt.Println(
-				color.Red.Render("\nConfiguration input aborted.\n"),
+				color.Red.Render("\n"+textForm.text("Configuration input aborted.")+"\n"),
 			)
-			os.Exit(1)
-		} else {
-			return err
 		}
+		return err
 	}
 	return nil
 }
@@ -72,6 +134,7 @@ func GetFormInput(
 func NewTextForm(
 	title, heading string,
 	input forms.Input,
+	options ...TextFormOption,
 ) (*TextForm, error) {
 
 	var (
@@ -83,81 +146,134 @@ func NewTextForm(
 		return nil, fmt.Errorf("input is not of type forms.InputGroup: %#v", input)
 	}
 
-	return &TextForm{
+	tf := &TextForm{
 		title:   title,
 		heading: heading,
 
 		inputGroup: inputGroup,
-	}, nil
+
+		in:  os.Stdin,
+		out: os.Stdout,
+	}
+	for _, option := range options {
+		option(tf)
+	}
+	return tf, nil
 }
 
+// out: an InputAbortedError if input was aborted
 func (tf *TextForm) GetInput(
 	indentSpaces, width int,
 	tags ...string,
 ) error {
 
 	var (
-		err    error
-		exists bool
+		err error
 
-		nameLen, l, j int
+		line LineEditor
+	)
 
-		cursor     *forms.InputCursor
-		inputField *forms.InputField
-		input      forms.Input
+	if line = tf.lineEditor; line == nil {
+		if tf.in == os.Stdin {
+			l := liner.NewLiner()
+			l.SetCtrlCAborts(true)
+			line = l
+		} else {
+			line = newScriptedLineEditor(tf.in, tf.out)
+		}
+		defer func() {
+			line.Close()
+		}()
+	}
 
-		doubleDivider, singleDivider,
-		prompt, response, suggestion,
-		envVal string
+	if err = tf.getInput(line, indentSpaces, width, tags...); err == liner.ErrPromptAborted || err == io.EOF {
+		return &InputAbortedError{
+			Form: tf.inputGroup.Name(),
+			Err:  err,
+		}
+	}
+	return err
+}
 
-		value *string
+func (tf *TextForm) getInput(
+	line LineEditor,
+	indentSpaces, width int,
+	tags ...string,
+) error {
 
-		valueFromFile bool
-		filePaths,
-		hintValues,
-		fieldHintValues []string
-	)
+	var (
+		err error
 
-	line := liner.NewLiner()
-	line.SetCtrlCAborts(true)
+		nameLen, l, j int
+
+		first, cursor *forms.InputCursor
+		inputField    *forms.InputField
+		input         forms.Input
 
-	defer func() {
-		line.Close()
-	}()
+		doubleDivider, singleDivider,
+		prompt, response string
+
+		valueFromFile,
+		fromHint bool
+	)
 
 	doubleDivider = strings.Repeat("=", width)
 	singleDivider = strings.Repeat("-", width)
 
 	tf.printFormHeader("", width)
-	fmt.Println(doubleDivider)
-	fmt.Println()
+	fmt.Fprintln(tf.out, doubleDivider)
+	fmt.Fprintln(tf.out)
 
 	promptInput := func(input forms.Input) {
 
-		fmt.Println(tf.getInputLongDescription(
+		fmt.Fprintln(tf.out, tf.getInputLongDescription(
 			input,
 			DescOnly,
 			"", "",
 			0, width, len(input.DisplayName()),
 		))
-		fmt.Println(singleDivider)
+		fmt.Fprintln(tf.out, singleDivider)
 		prompt = ": "
 	}
 
-	cursor = forms.NewInputCursor(tf.inputGroup, tags...)
-	cursor = cursor.NextInput()
+	first = forms.NewInputCursor(tf.inputGroup, tags...)
+	cursor = first.NextInput()
 
-	for cursor != nil {
+	for {
+		if cursor == nil {
+			// all inputs have been collected so the form
+			// is validated as a whole and the user is sent
+			// back to the first field that was entered and
+			// whose value violates a validation rule
+			if cursor, err = tf.validateInput(first); cursor == nil {
+				return err
+			}
+		}
 		if input, err = cursor.GetCurrentInput(); err != nil {
 			return err
 		}
 
+		if input.Type() == forms.Repeatable {
+			// the cursor returns to a repeatable group after
+			// each item so the user can add or remove items
+			// until continuing to the next input
+			if cursor, err = tf.promptRepeatable(
+				line, cursor,
+				input.(*forms.RepeatableGroup),
+				width,
+			); err != nil {
+				return err
+			}
+			cursor = cursor.NextInput()
+			continue
+		}
+
 		if input.Type() == forms.Container {
 
 			inputs := input.EnabledInputs(true, tags...)
 			if len(inputs) > 1 {
-				fmt.Println(input.Description())
-				fmt.Println(doubleDivider)
+				fmt.Fprintln(tf.out, input.Description())
+				fmt.Fprintln(tf.out, doubleDivider)
 
 				// normalize display name length
 				// of all input group fields
@@ -176,13 +292,13 @@ func (tf *TextForm) GetInput(
 				for i, ii := range inputs {
 
 					options[i] = strconv.Itoa(i + 1)
-					fmt.Println(tf.getInputLongDescription(
+					fmt.Fprintln(tf.out, tf.getInputLongDescription(
 						ii,
 						DescOnly,
 						"", fmt.Sprintf("%s. ", options[i]),
 						0, width, l,
 					))
-					fmt.Println(singleDivider)
+					fmt.Fprintln(tf.out, singleDivider)
 				}
 
 				line.SetCompleter(func(line string) (c []string) {
@@ -190,7 +306,7 @@ func (tf *TextForm) GetInput(
 					return options
 				})
 				for {
-					if response, err = line.Prompt("Please select one of the above ? "); err != nil {
+					if response, err = line.Prompt(tf.text("Please select one of the above ?") + " "); err != nil {
 						return err
 					}
 					if j, err = strconv.Atoi(response); err == nil {
@@ -198,7 +314,7 @@ func (tf *TextForm) GetInput(
 					}
 				}
 
-				fmt.Println(singleDivider)
+				fmt.Fprintln(tf.out, singleDivider)
 				input = inputs[j-1]
 				prompt = input.DisplayName() + " : "
 
@@ -222,106 +338,434 @@ func (tf *TextForm) GetInput(
 
 		if input != nil {
 			inputField = input.(*forms.InputField)
-			value = inputField.Value()
-
-			valueFromFile, filePaths = inputField.ValueFromFile()
-			if valueFromFile {
-
-				// if value for the field is sourced from a file then
-				// create a list of auto-completion hints with default
-				// values from the environment
-				if value != nil {
-					hintValues = append(filePaths, []string{"", "[saved]"}...)
-				} else {
-					hintValues = append(filePaths, "")
-				}
-				suggestion = hintValues[len(hintValues)-1]
-
-			} else {
-
-				if values := inputField.AcceptedValues(); values != nil {
-					// if values are restrcted to a given list then
-					// create a list of auto-completion hints only
-					// with those values
-					hintValues = values
-					if value != nil {
-						suggestion = *value
-					} else {
-						suggestion = ""
-					}
-
-				} else {
-					// create a list of auto-completion hints from
-					// the environment variable associated with the
-					// input field along with any values retrieved
-					// from any field hints set in the input group.
-					hintValues = []string{}
-
-					// set of added values used to ensure
-					// the same values are not added twice
-					valueSet := map[string]bool{"": true}
-					if value != nil && len(*value) > 0 {
-						valueSet[*value] = true
-					}
-
-					// add values sourced from environment to completion list
-					for _, e := range inputField.EnvVars() {
-						if envVal, exists = os.LookupEnv(e); exists {
-							if _, exists = valueSet[envVal]; !exists {
-								hintValues = append(hintValues, envVal)
-								valueSet[envVal] = true
-							}
-						}
-					}
-
-					// add values sourced from hints to completion list
-					if fieldHintValues, err = tf.inputGroup.GetFieldValueHints(input.Name()); err != nil {
-						logger.DebugMessage(
-							"Error retrieving hint values for field '%s': '%s'",
-							input.Name(), err.Error())
-					}
-					hintValues = append(append(hintValues, fieldHintValues...), "")
-					if value != nil {
-						hintValues = append(hintValues, *value)
-					}
-					suggestion = hintValues[len(hintValues)-1]
-				}
-			}
-
-			line.SetCompleter(func(line string) []string {
-				filteredHintValues := []string{}
-				for _, v := range hintValues {
-					if strings.HasPrefix(v, strings.ToLower(line)) {
-						filteredHintValues = append(filteredHintValues, v)
-					}
-				}
-				return filteredHintValues
-			})
-			if response, err = line.PromptWithSuggestion(prompt, suggestion, -1); err != nil {
+			if response, fromHint, err = tf.promptInputValue(line, inputField, prompt); err != nil {
 				return err
 			}
+			if response == BackResponse {
+				// return to the previous input so a
+				// value entered earlier can be changed
+				cursor = tf.prevInput(cursor, tags...)
+				fmt.Fprintln(tf.out)
+				continue
+			}
+			valueFromFile, _ = inputField.ValueFromFile()
 
 			// set input with entered value
 			if valueFromFile && response == "[saved]" {
 				if cursor, err = cursor.SetDefaultInput(input.Name()); err != nil {
 					return err
 				}
+			} else if fromHint {
+				if cursor, err = cursor.SetInputFromHint(input.Name(), response); err != nil {
+					return err
+				}
 			} else {
 				if cursor, err = cursor.SetInput(input.Name(), response); err != nil {
 					return err
 				}
 			}
 			if valueFromFile && !inputField.Sensitive() {
-				fmt.Printf("Value from file: \n%s\n", *inputField.Value())
+				fmt.Fprintf(tf.out, "%s \n%s\n", tf.text("Value from file:"), *inputField.Value())
 			}
 
-			fmt.Println()
+			fmt.Fprintln(tf.out)
 		}
 
 		cursor = cursor.NextInput()
 	}
+}
 
-	return nil
+// in: cursor - a cursor of the inputs that have been collected
+// out: the cursor moved back to the first field that was entered
+//      and whose value violates a validation rule of the form.
+//      nil if the form is valid or no field can be moved back to.
+func (tf *TextForm) validateInput(cursor *forms.InputCursor) (*forms.InputCursor, error) {
+
+	var (
+		err error
+
+		validationErr *forms.FormValidationError
+		inputField    *forms.InputField
+		fieldCursor   *forms.InputCursor
+	)
+
+	if err = tf.inputGroup.Validate(); err == nil {
+		return nil, nil
+	}
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+	for _, name := range validationErr.Fields() {
+		if inputField, err = tf.inputGroup.GetInputField(name); err != nil || !inputField.InputSet() {
+			continue
+		}
+		// moving back through the cursor clears the fields
+		// that depend on the field so they are prompted for
+		// again if the new value changes which are enabled
+		if fieldCursor, err = cursor.Rewind(name); err != nil {
+			continue
+		}
+		for _, violation := range validationErr.Violations[name] {
+			fmt.Fprintln(tf.out, color.Red.Render(violation.Error()))
+		}
+		return fieldCursor, nil
+	}
+	return nil, validationErr
+}
+
+// in: cursor - the cursor at the input being prompted for
+// in: tags   - the tags of the inputs being prompted for
+// out: the cursor at the previous input that was prompted
+//      for or at the first input if there is no such input
+func (tf *TextForm) prevInput(
+	cursor *forms.InputCursor,
+	tags ...string,
+) *forms.InputCursor {
+
+	var (
+		err error
+
+		prev  *forms.InputCursor
+		input forms.Input
+	)
+
+	// inputs that were skipped because they
+	// were disabled are moved back past
+	for prev = cursor.PrevInput(); prev != nil; prev = prev.PrevInput() {
+		cursor = prev
+		if input, err = cursor.GetCurrentInput(); err != nil {
+			break
+		}
+		switch input.Type() {
+		case forms.Container:
+			if len(input.EnabledInputs(true, tags...)) > 0 {
+				return cursor
+			}
+		case forms.Repeatable:
+			return cursor
+		default:
+			if input.Enabled(true, tags...) {
+				return cursor
+			}
+		}
+	}
+	return cursor
+}
+
+// in: line   - the line editor to prompt with
+// in: cursor - cursor positioned at the repeatable group
+// in: rg     - the repeatable group to prompt for
+// in: width  - the width of the form
+// out: a cursor for a new item if one should be added
+//      or the given cursor to continue to the next input
+func (tf *TextForm) promptRepeatable(
+	line LineEditor,
+	cursor *forms.InputCursor,
+	rg *forms.RepeatableGroup,
+	width int,
+) (*forms.InputCursor, error) {
+
+	var (
+		err error
+
+		response string
+		j        int
+	)
+
+	doubleDivider := strings.Repeat("=", width)
+	singleDivider := strings.Repeat("-", width)
+
+	for {
+		items := rg.Items()
+
+		fmt.Fprintln(tf.out, tf.getInputLongDescription(
+			rg,
+			DescOnly,
+			"", "",
+			0, width, len(rg.DisplayName()),
+		))
+		fmt.Fprintln(tf.out, doubleDivider)
+		for i, item := range items {
+			fmt.Fprintf(tf.out, "%d. %s\n", i+1, itemSummary(item))
+			fmt.Fprintln(tf.out, singleDivider)
+		}
+
+		options := []string{}
+		if rg.MaxItems() == 0 || len(items) < rg.MaxItems() {
+			options = append(options, "a")
+		}
+		if len(items) > 0 {
+			options = append(options, "r")
+		}
+		if len(items) >= rg.MinItems() {
+			options = append(options, "n")
+		}
+		line.SetCompleter(func(line string) (c []string) {
+			return options
+		})
+
+		selected := false
+		for !selected {
+			if response, err = line.Prompt(
+				fmt.Sprintf(
+					tf.text("Add an item, remove an item or continue to the next input ? [%s]")+" ",
+					strings.Join(options, "/"),
+				),
+			); err != nil {
+				return nil, err
+			}
+			response = strings.ToLower(strings.TrimSpace(response))
+			for _, o := range options {
+				selected = selected || o == response
+			}
+		}
+		fmt.Fprintln(tf.out)
+
+		switch response {
+		case "a":
+			if cursor, err = cursor.AddItem(rg.Name()); err != nil {
+				return nil, err
+			}
+			items = rg.Items()
+			fmt.Fprintln(tf.out, items[len(items)-1].DisplayName())
+			fmt.Fprintln(tf.out, doubleDivider)
+			fmt.Fprintln(tf.out)
+			return cursor, nil
+
+		case "r":
+			for {
+				if response, err = line.Prompt(tf.text("Item to remove ?") + " "); err != nil {
+					return nil, err
+				}
+				if j, err = strconv.Atoi(response); err == nil && j > 0 && j <= len(items) {
+					break
+				}
+			}
+			if err = rg.RemoveItem(j - 1); err != nil {
+				return nil, err
+			}
+			fmt.Fprintln(tf.out)
+
+		default:
+			return cursor, nil
+		}
+	}
+}
+
+// in: item - input group of an item of a repeatable group
+// out: a single line summary of the values of the item
+func itemSummary(item *forms.InputGroup) string {
+
+	var (
+		out strings.Builder
+	)
+
+	for _, f := range item.InputFields() {
+		if value := f.Value(); f.InputSet() && value != nil {
+			if out.Len() > 0 {
+				out.WriteString(", ")
+			}
+			out.WriteString(f.DisplayName())
+			out.WriteString(" = ")
+			if f.Sensitive() {
+				out.WriteString("****")
+			} else {
+				out.WriteString(*value)
+			}
+		}
+	}
+	return out.String()
+}
+
+// in: line       - the line editor to prompt with
+// in: inputField - the field to prompt a value for
+// in: prompt     - the prompt to display
+// out: the response entered for the field
+// out: whether the response is one of the field's hint values
+func (tf *TextForm) promptInputValue(
+	line LineEditor,
+	inputField *forms.InputField,
+	prompt string,
+) (string, bool, error) {
+
+	if valueFromFile, _ := inputField.ValueFromFile(); inputField.ListInput() && !valueFromFile {
+		response, err := tf.promptListValue(line, inputField, prompt)
+		return response, false, err
+	}
+	return tf.promptFieldValue(line, inputField, prompt, inputField.Value())
+}
+
+// in: line       - the line editor to prompt with
+// in: inputField - the list field to prompt items for
+// in: prompt     - the prompt to display
+// out: a json array of the items entered for the field
+func (tf *TextForm) promptListValue(
+	line LineEditor,
+	inputField *forms.InputField,
+	prompt string,
+) (string, error) {
+
+	var (
+		err error
+
+		response string
+		data     []byte
+		value    *string
+	)
+
+	// the current items of the field are
+	// suggested as each item is entered
+	currentItems := inputField.Values()
+	items := []string{}
+
+	itemPrompt := strings.TrimSuffix(prompt, ": ")
+	for {
+		value = nil
+		if len(items) < len(currentItems) {
+			value = &currentItems[len(items)]
+		}
+		if response, _, err = tf.promptFieldValue(
+			line, inputField,
+			fmt.Sprintf("%s#%d : ", itemPrompt, len(items)+1),
+			value,
+		); err != nil {
+			return "", err
+		}
+		if err = inputField.ValidateItem(response); err != nil {
+			fmt.Fprintln(tf.out, color.Red.Render(err.Error()))
+			continue
+		}
+		items = append(items, response)
+
+		if inputField.MaxItems() > 0 && len(items) >= inputField.MaxItems() {
+			break
+		}
+		if len(items) >= inputField.MinItems() {
+			if response, err = line.Prompt(tf.text("Add another? [y/N]") + " "); err != nil {
+				return "", err
+			}
+			if response = strings.ToLower(strings.TrimSpace(response)); response != "y" && response != "yes" {
+				break
+			}
+		}
+	}
+
+	if data, err = json.Marshal(items); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// in: line       - the line editor to prompt with
+// in: inputField - the field to prompt a value for
+// in: prompt     - the prompt to display
+// in: value      - the current value to suggest
+// out: the response entered for the field
+// out: whether the response is one of the field's hint values
+func (tf *TextForm) promptFieldValue(
+	line LineEditor,
+	inputField *forms.InputField,
+	prompt string,
+	value *string,
+) (string, bool, error) {
+
+	var (
+		err    error
+		exists bool
+
+		suggestion,
+		envVal,
+		response string
+
+		valueFromFile bool
+		filePaths,
+		hintValues,
+		fieldHintValues []string
+	)
+
+	valueFromFile, filePaths = inputField.ValueFromFile()
+	if valueFromFile {
+
+		// if value for the field is sourced from a file then
+		// create a list of auto-completion hints with default
+		// values from the environment
+		if value != nil {
+			hintValues = append(filePaths, []string{"", "[saved]"}...)
+		} else {
+			hintValues = append(filePaths, "")
+		}
+		suggestion = hintValues[len(hintValues)-1]
+
+	} else {
+
+		if values := inputField.AcceptedValues(); values != nil {
+			// if values are restrcted to a given list then
+			// create a list of auto-completion hints only
+			// with those values
+			hintValues = values
+			if value != nil {
+				suggestion = *value
+			} else {
+				suggestion = ""
+			}
+
+		} else {
+			// create a list of auto-completion hints from
+			// the environment variable associated with the
+			// input field along with any values retrieved
+			// from any field hints set in the input group.
+			hintValues = []string{}
+
+			// set of added values used to ensure
+			// the same values are not added twice
+			valueSet := map[string]bool{"": true}
+			if value != nil && len(*value) > 0 {
+				valueSet[*value] = true
+			}
+
+			// add values sourced from environment to completion list
+			for _, e := range inputField.EnvVars() {
+				if envVal, exists = os.LookupEnv(e); exists {
+					if _, exists = valueSet[envVal]; !exists {
+						hintValues = append(hintValues, envVal)
+						valueSet[envVal] = true
+					}
+				}
+			}
+
+			// add values sourced from hints to completion list
+			if fieldHintValues, err = tf.inputGroup.GetFieldValueHints(inputField.Name()); err != nil {
+				logger.DebugMessage(
+					"Error retrieving hint values for field '%s': '%s'",
+					inputField.Name(), err.Error())
+			}
+			hintValues = append(append(hintValues, fieldHintValues...), "")
+			if value != nil {
+				hintValues = append(hintValues, *value)
+			}
+			suggestion = hintValues[len(hintValues)-1]
+		}
+	}
+
+	line.SetCompleter(func(line string) []string {
+		filteredHintValues := []string{}
+		for _, v := range hintValues {
+			if strings.HasPrefix(v, strings.ToLower(line)) {
+				filteredHintValues = append(filteredHintValues, v)
+			}
+		}
+		return filteredHintValues
+	})
+
+	if response, err = line.PromptWithSuggestion(prompt, suggestion, -1); err != nil {
+		return "", false, err
+	}
+	for _, v := range fieldHintValues {
+		if v == response {
+			return response, true, nil
+		}
+	}
+	return response, false, nil
 }
 
 func (tf *TextForm) ShowInputReference(
@@ -339,7 +783,7 @@ func (tf *TextForm) ShowInputReference(
 	)
 
 	padding = strings.Repeat(" ", startIndent)
-	evalFieldDeps = fieldShowOption == DescAndValues
+	evalFieldDeps = fieldShowOption == DescAndValues || fieldShowOption == DescValuesAndSources
 
 	fieldLengths = make(map[string]*int)
 	tf.calcNameLengths(tf.inputGroup, fieldLengths, nil, true, tags...)
@@ -359,25 +803,25 @@ func (tf *TextForm) ShowInputReference(
 			return
 		}
 
-		fmt.Println()
+		fmt.Fprintln(tf.out)
 		if input.Type() == forms.Container {
 
 			// output description of a group of
 			// inputs which are mutually exclusive
 
-			fmt.Print(padding)
-			utils.RepeatString(" ", level*indentSpaces, os.Stdout)
-			fmt.Printf("* Provide one of the following for:\n\n")
+			fmt.Fprint(tf.out, padding)
+			utils.RepeatString(" ", level*indentSpaces, tf.out)
+			fmt.Fprintf(tf.out, "* %s\n\n", tf.text("Provide one of the following for:"))
 
 			levelIndent = strings.Repeat(" ", (level+1)*indentSpaces)
 
-			fmt.Print(padding)
-			fmt.Print(levelIndent)
-			fmt.Print(input.Description())
+			fmt.Fprint(tf.out, padding)
+			fmt.Fprint(tf.out, levelIndent)
+			fmt.Fprint(tf.out, input.Description())
 
 		} else {
 
-			fmt.Print(tf.getInputLongDescription(
+			fmt.Fprint(tf.out, tf.getInputLongDescription(
 				input,
 				fieldShowOption,
 				padding, "* ",
@@ -390,18 +834,18 @@ func (tf *TextForm) ShowInputReference(
 
 			if input.Type() == forms.Container {
 				if i > 0 {
-					fmt.Print("\n\n")
-					fmt.Print(padding)
-					fmt.Print(levelIndent)
-					fmt.Print("OR\n")
+					fmt.Fprint(tf.out, "\n\n")
+					fmt.Fprint(tf.out, padding)
+					fmt.Fprint(tf.out, levelIndent)
+					fmt.Fprint(tf.out, tf.text("OR")+"\n")
 				} else {
-					fmt.Print("\n")
+					fmt.Fprint(tf.out, "\n")
 				}
 				printInput(level+1, ii)
 
 			} else {
 				if ii.Type() == forms.Container {
-					fmt.Print("\n")
+					fmt.Fprint(tf.out, "\n")
 				}
 				printInput(level, ii)
 			}
@@ -415,7 +859,7 @@ func (tf *TextForm) ShowInputReference(
 			// also had a container at the end of its inputs two newlines
 			// will be output when only on newline should have been output
 			if l == 0 || inputs[l-1].Type() != forms.Container {
-				fmt.Print("\n")
+				fmt.Fprint(tf.out, "\n")
 			}
 		}
 	}
@@ -426,6 +870,12 @@ func (tf *TextForm) ShowInputReference(
 	}
 }
 
+// in: text - a text of the form
+// out: the text translated to the form's locale
+func (tf *TextForm) text(text string) string {
+	return tf.inputGroup.Translate(text)
+}
+
 func (tf *TextForm) printFormHeader(
 	padding string,
 	width int,
@@ -440,18 +890,18 @@ func (tf *TextForm) printFormHeader(
 	text.WriteString(padding)
 	utils.RepeatString("=", len(tf.title), &text)
 
-	fmt.Print(color.OpBold.Render(text.String()))
-	fmt.Print("\n\n")
+	fmt.Fprint(tf.out, color.OpBold.Render(text.String()))
+	fmt.Fprint(tf.out, "\n\n")
 
-	fmt.Print(padding)
-	fmt.Print(tf.inputGroup.Description())
-	fmt.Print("\n\n")
+	fmt.Fprint(tf.out, padding)
+	fmt.Fprint(tf.out, tf.inputGroup.Description())
+	fmt.Fprint(tf.out, "\n\n")
 
 	if len(tf.heading) > 0 {
 		l := len(padding)
 		s, _ := utils.FormatMultilineString(tf.heading, l, width-l, true, true)
-		fmt.Print(color.OpItalic.Render(s))
-		fmt.Println()
+		fmt.Fprint(tf.out, color.OpItalic.Render(s))
+		fmt.Fprintln(tf.out)
 	}
 }
 
@@ -483,11 +933,15 @@ func (tf *TextForm) getInputLongDescription(
 	out.WriteString(name)
 
 	utils.RepeatString(" ", nameLen-len(name), &out)
-	if fieldShowOption == DescAndValues && input.Type() != forms.Container {
+	showValues := fieldShowOption == DescAndValues || fieldShowOption == DescValuesAndSources
+	if showValues && input.Type() != forms.Container {
 		out.WriteString(" = ")
 		l = len(out.String())
 
-		if field, ok = input.(*forms.InputField); ok {
+		if rg, isRepeatable := input.(*forms.RepeatableGroup); isRepeatable {
+			out.WriteString(fmt.Sprintf("%d item(s)", len(rg.Items())))
+
+		} else if field, ok = input.(*forms.InputField); ok {
 			if value = field.Value(); value != nil {
 				if field.Sensitive() {
 					out.WriteString("****")
@@ -495,8 +949,13 @@ func (tf *TextForm) getInputLongDescription(
 					output, _ = utils.FormatMultilineString(*value, l, width-l, false, true)
 					out.WriteString(output)
 				}
+				if fieldShowOption == DescValuesAndSources {
+					out.WriteString(" ")
+					out.WriteString(color.OpItalic.Render(
+						fmt.Sprintf(tf.text("(from %s)"), field.ValueSource())))
+				}
 			} else {
-				out.WriteString("[no data]")
+				out.WriteString(tf.text("[no data]"))
 			}
 		}
 
@@ -521,12 +980,12 @@ func (tf *TextForm) getInputLongDescription(
 
 					if field.Sensitive() {
 						output, _ = utils.FormatMultilineString(
-							"(Default value = '****')", 
+							fmt.Sprintf(tf.text("(Default value = '%s')"), "****"),
 							l, width-l, true, true)
 						out.WriteString(output)
 					} else {
 						output, _ = utils.FormatMultilineString(
-							fmt.Sprintf("(Default value = '%s')", *value), 
+							fmt.Sprintf(tf.text("(Default value = '%s')"), *value), 
 							l, width-l, true, true)
 						out.WriteString(output)
 					}
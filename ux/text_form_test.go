@@ -3,14 +3,18 @@ package ux_test
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/gookit/color"
+
 	"github.com/mevansam/goforms/forms"
 	"github.com/mevansam/goforms/ux"
 	"github.com/mevansam/goutils/logger"
@@ -126,6 +130,31 @@ var _ = Describe("Text Formatting tests", func() {
 
 			testFormOutput(ux.DescAndValues, testFormOutputWithValues)
 		})
+
+		It("outputs a detailed input data form with where field values came from", func() {
+
+			var (
+				output bytes.Buffer
+			)
+
+			_ = inputGroup.SetFieldValue("attrib12", "value for attrib12")
+			os.Setenv("ATTRIB11_ENV2", "value for attrib11")
+			defer os.Unsetenv("ATTRIB11_ENV2")
+
+			tf, err := ux.NewTextForm(
+				"Input Data Form for 'input-form'",
+				"CONFIGURATION DATA INPUT",
+				inputGroup,
+				ux.WithIO(strings.NewReader(""), &output, nil),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			tf.ShowInputReference(ux.DescValuesAndSources, 2, 2, 80)
+
+			Expect(output.String()).To(ContainSubstring("value for attrib11 "))
+			Expect(output.String()).To(ContainSubstring("(from env:ATTRIB11_ENV2)"))
+			Expect(output.String()).To(ContainSubstring("value for attrib12 "))
+			Expect(output.String()).To(ContainSubstring("(from bound)"))
+		})
 	})
 
 	Context("Input", func() {
@@ -206,6 +235,52 @@ var _ = Describe("Text Formatting tests", func() {
 			Expect(utils.WaitTimeout(&wg, time.Second)).To(BeTrue())
 		}
 
+		// the items of a list field are prompted for on the
+		// same output line so the output cannot be matched
+		// line by line as each input is entered. all input
+		// is written up front and the output is compared
+		// once the form's input is complete.
+		var testFormItemInput = func(testFormInputPrompts string, expectedValues map[string]string) {
+
+			var (
+				input, expected bytes.Buffer
+			)
+
+			expectReader := bufio.NewScanner(bytes.NewBufferString(testFormInputPrompts))
+			for expectReader.Scan() {
+				line := expectReader.Text()
+				if i := strings.Index(line, "<<"); i != -1 {
+					expected.WriteString(line[:i])
+					input.WriteString(line[i+2:] + "\n")
+				} else {
+					expected.WriteString(line + "\n")
+				}
+			}
+			_, err = stdInWriter.Write(input.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+
+			out := make(chan string)
+			go func() {
+				var output bytes.Buffer
+				_, _ = io.Copy(&output, stdOutReader)
+				out <- output.String()
+			}()
+
+			tf, err := ux.NewTextForm(
+				"Input Data Form for 'input-form'",
+				"CONFIGURATION DATA INPUT",
+				inputGroup,
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tf.GetInput(2, 80)).To(Succeed())
+
+			os.Stdout.Close()
+			output := <-out
+			logger.DebugMessage("\n%s\n", output)
+			Expect(output).To(Equal(expected.String()))
+			Expect(inputGroup.InputValues()).To(Equal(expectedValues))
+		}
+
 		It("gathers input for the form from stdin #1", func() {
 
 			expectedValues := map[string]string{
@@ -235,6 +310,245 @@ var _ = Describe("Text Formatting tests", func() {
 
 			testFormInput(testFormInputPrompts2, expectedValues)
 		})
+
+		It("records values chosen from the hints of a field", func() {
+
+			tmpDir, err := os.MkdirTemp("", "goforms")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(tmpDir)
+
+			path := filepath.Join(tmpDir, "hints")
+			Expect(os.WriteFile(path, []byte("value for attrib12\nvalue for attrib12 - B\n"), 0600)).To(Succeed())
+			Expect(inputGroup.AddFieldValueHint("attrib12", "file://"+path)).To(Succeed())
+
+			expectedValues := map[string]string{
+				"attrib12":   "value for attrib12",
+				"attrib122":  "value for attrib122",
+				"attrib1221": "value for attrib1221",
+				"attrib131":  "value for attrib131",
+				"attrib1311": "value for attrib1311",
+				"attrib1312": "value for attrib1312",
+				"attrib14":   "value for attrib14",
+			}
+
+			testFormInput(testFormInputPrompts1, expectedValues)
+
+			sources := inputGroup.InputValueSources()
+			Expect(sources["attrib12"]).To(Equal(forms.ValueSource{Type: forms.HintSource}))
+			Expect(sources["attrib122"]).To(Equal(forms.ValueSource{Type: forms.BoundSource}))
+		})
+
+		It("sends the user back to fields that violate the form's validation rules", func() {
+
+			inputGroup.AddValidator(func(form forms.InputForm) map[string]error {
+				v1, _ := form.GetFieldValue("attrib1311")
+				v2, _ := form.GetFieldValue("attrib1312")
+				if v1 != nil && v2 != nil && *v1 == *v2 {
+					return map[string]error{
+						"attrib1312": fmt.Errorf("attrib1312 must differ from attrib1311"),
+					}
+				}
+				return nil
+			})
+
+			expectedValues := map[string]string{
+				"attrib12":   "value for attrib12 - A",
+				"attrib121":  "value for attrib121",
+				"attrib131":  "value for attrib131",
+				"attrib1311": "value for attrib1311",
+				"attrib1312": "value for attrib1312",
+				"attrib14":   "value for attrib14 - X",
+				"attrib141":  "value for attrib141",
+			}
+
+			testFormInput(testFormInputPrompts3, expectedValues)
+		})
+
+		It("gathers the items of a list field one at a time", func() {
+
+			field, err := inputGroup.GetInputField("attrib14")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(field.SetListInput(1, 3)).To(Succeed())
+			Expect(field.SetExclusionFilter(`!`, "items may not contain a '!'")).To(Succeed())
+
+			expectedValues := map[string]string{
+				"attrib12":   "value for attrib12",
+				"attrib122":  "value for attrib122",
+				"attrib1221": "value for attrib1221",
+				"attrib131":  "value for attrib131",
+				"attrib1311": "value for attrib1311",
+				"attrib1312": "value for attrib1312",
+				"attrib14":   `["item 1","item 2"]`,
+			}
+
+			testFormItemInput(testFormInputPrompts4, expectedValues)
+		})
+
+		It("gathers the items of a repeatable group", func() {
+
+			inputGroup = forms.NewInputCollection().NewGroup("network", "network settings")
+			_, err = inputGroup.NewInputField(forms.FieldAttributes{
+				Name:        "hostname",
+				DisplayName: "Hostname",
+				Description: "the host name.",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			err = inputGroup.BindFields(&struct {
+				Hostname string `form_field:"hostname"`
+			}{})
+			Expect(err).NotTo(HaveOccurred())
+
+			rg, err := inputGroup.NewRepeatableGroup("interfaces", "Interface", "network interfaces", 1, 2)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = rg.NewInputField(forms.FieldAttributes{
+				Name:        "name",
+				DisplayName: "Name",
+				Description: "the interface name.",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = rg.NewInputField(forms.FieldAttributes{
+				Name:        "ip",
+				DisplayName: "IP",
+				Description: "the interface address.",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			expectedValues := map[string]string{
+				"hostname":   "host1",
+				"interfaces": `[{"ip":"10.0.1.2","name":"eth1"}]`,
+			}
+
+			testFormInput(testFormInputPrompts5, expectedValues)
+		})
+
+		It("returns to the previous input to change its value", func() {
+
+			expectedValues := map[string]string{
+				"attrib12":   "value for attrib12 - A",
+				"attrib121":  "changed value for attrib121",
+				"attrib131":  "value for attrib131",
+				"attrib1311": "value for attrib1311",
+				"attrib1312": "value for attrib1312",
+				"attrib14":   "value for attrib14 - X",
+				"attrib141":  "value for attrib141",
+			}
+
+			testFormInput(testFormInputPrompts6, expectedValues)
+		})
+	})
+
+	Context("Scripted input", func() {
+
+		// out: the responses to the prompts of an input script
+		var scriptResponses = func(testFormInputPrompts string) string {
+
+			var (
+				responses strings.Builder
+			)
+
+			for _, line := range strings.Split(testFormInputPrompts, "\n") {
+				if i := strings.Index(line, "<<"); i != -1 {
+					responses.WriteString(line[i+2:])
+					responses.WriteString("\n")
+				}
+			}
+			return responses.String()
+		}
+
+		// compares output with a golden file in the test fixtures.
+		// the golden file is written instead if UPDATE_GOLDEN is set.
+		var expectGoldenFile = func(name, output string) {
+
+			path := filepath.Join("..", "test", "fixtures", "ux", name)
+			if len(os.Getenv("UPDATE_GOLDEN")) > 0 {
+				Expect(os.WriteFile(path, []byte(output), 0644)).To(Succeed())
+			}
+			golden, err := os.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output).To(Equal(string(golden)))
+		}
+
+		It("writes a transcript of the input to the form's writer", func() {
+
+			var (
+				transcript bytes.Buffer
+			)
+
+			tf, err := ux.NewTextForm(
+				"Input Data Form for 'input-form'",
+				"CONFIGURATION DATA INPUT",
+				inputGroup,
+				ux.WithIO(strings.NewReader(scriptResponses(testFormInputPrompts2)), &transcript, nil),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tf.GetInput(2, 80)).To(Succeed())
+			expectGoldenFile("text_form_transcript.golden", transcript.String())
+
+			Expect(inputGroup.InputValues()).To(Equal(map[string]string{
+				"attrib12":   "value for attrib12 - A",
+				"attrib121":  "value for attrib121",
+				"attrib131":  "value for attrib131",
+				"attrib1311": "value for attrib1311",
+				"attrib1312": "value for attrib1312",
+				"attrib14":   "value for attrib14 - X",
+				"attrib141":  "value for attrib141",
+			}))
+		})
+
+		It("translates the form's texts to its locale", func() {
+
+			var (
+				transcript bytes.Buffer
+			)
+
+			inputGroup.AddCatalog("de", &forms.Catalog{
+				Inputs: map[string]forms.Translation{
+					"attrib11": {DisplayName: "Attribut 11", Description: "Beschreibung für attrib11."},
+				},
+				Messages: map[string]string{
+					"Please select one of the above ?": "Bitte wählen Sie eine der obigen Optionen ?",
+				},
+			})
+			inputGroup.SetLocale("de-DE")
+
+			tf, err := ux.NewTextForm(
+				"Input Data Form for 'input-form'",
+				"CONFIGURATION DATA INPUT",
+				inputGroup,
+				ux.WithIO(strings.NewReader(scriptResponses(testFormInputPrompts2)), &transcript, nil),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tf.GetInput(2, 80)).To(Succeed())
+
+			Expect(transcript.String()).To(ContainSubstring(
+				"1. Attribut 11 - Beschreibung für attrib11. It will be sourced from the"))
+			Expect(transcript.String()).To(ContainSubstring(
+				"Bitte wählen Sie eine der obigen Optionen ? 2\n"))
+			Expect(transcript.String()).ToNot(ContainSubstring("Please select"))
+		})
+
+		It("returns an error when the input is aborted", func() {
+
+			var (
+				transcript bytes.Buffer
+
+				abortedErr *ux.InputAbortedError
+			)
+
+			tf, err := ux.NewTextForm(
+				"Input Data Form for 'input-form'",
+				"CONFIGURATION DATA INPUT",
+				inputGroup,
+				ux.WithIO(strings.NewReader("2\nvalue for attrib12\n"), &transcript, nil),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = tf.GetInput(2, 80)
+			Expect(errors.As(err, &abortedErr)).To(BeTrue())
+			Expect(abortedErr.Form).To(Equal("input-form"))
+			Expect(errors.Is(err, io.EOF)).To(BeTrue())
+			Expect(transcript.String()).To(HaveSuffix("Please select one of the above ? \n"))
+		})
 	})
 })
 
@@ -473,3 +787,111 @@ Attrib 141 - description for attrib141.
 --------------------------------------------------------------------------------
 : <<value for attrib141
 `
+
+var testFormInputPrompts3 = strings.Replace(
+	testFormInputPrompts2,
+	": <<value for attrib1312\n",
+	": <<value for attrib1311\n", 1,
+) + `
+` + color.Red.Render("attrib1312 must differ from attrib1311") + `
+Attrib 1312 - description for attrib1312.
+--------------------------------------------------------------------------------
+: <<value for attrib1312
+
+Attrib 14 - description for attrib14.
+--------------------------------------------------------------------------------
+: <<value for attrib14 - X
+
+Attrib 141 - description for attrib141.
+--------------------------------------------------------------------------------
+: <<value for attrib141
+`
+
+var testFormInputPrompts4 = strings.Replace(
+	testFormInputPrompts1,
+	": <<value for attrib14\n",
+	`#1 : <<item!
+`+color.Red.Render("items may not contain a '!'")+`
+#1 : <<item 1
+Add another? [y/N] <<y
+#2 : <<item 2
+Add another? [y/N] <<n
+`, 1,
+) + "\n"
+
+const testFormInputPrompts5 = term.BOLD + `Input Data Form for 'input-form'
+================================` + term.NC + `
+
+network settings
+
+` + term.ITALIC + `CONFIGURATION DATA INPUT` + term.NC + `
+================================================================================
+
+Hostname - the host name.
+--------------------------------------------------------------------------------
+: <<host1
+
+Interface - network interfaces
+================================================================================
+Add an item, remove an item or continue to the next input ? [a] <<a
+
+Interface #1
+================================================================================
+
+Name - the interface name.
+--------------------------------------------------------------------------------
+: <<eth0
+
+IP - the interface address.
+--------------------------------------------------------------------------------
+: <<10.0.0.2
+
+Interface - network interfaces
+================================================================================
+1. Name = eth0, IP = 10.0.0.2
+--------------------------------------------------------------------------------
+Add an item, remove an item or continue to the next input ? [a/r/n] <<a
+
+Interface #2
+================================================================================
+
+Name - the interface name.
+--------------------------------------------------------------------------------
+: <<eth1
+
+IP - the interface address.
+--------------------------------------------------------------------------------
+: <<10.0.1.2
+
+Interface - network interfaces
+================================================================================
+1. Name = eth0, IP = 10.0.0.2
+--------------------------------------------------------------------------------
+2. Name = eth1, IP = 10.0.1.2
+--------------------------------------------------------------------------------
+Add an item, remove an item or continue to the next input ? [r/n] <<r
+
+Item to remove ? <<1
+
+Interface - network interfaces
+================================================================================
+1. Name = eth1, IP = 10.0.1.2
+--------------------------------------------------------------------------------
+Add an item, remove an item or continue to the next input ? [a/r/n] <<n
+
+`
+
+var testFormInputPrompts6 = strings.Replace(
+	testFormInputPrompts2,
+	": <<value for attrib131\n",
+	`: <<`+ux.BackResponse+`
+
+Attrib 121 - description for attrib121.
+--------------------------------------------------------------------------------
+: <<changed value for attrib121
+
+Attrib 131 - description for attrib131.
+--------------------------------------------------------------------------------
+: <<value for attrib131
+`, 1,
+)
@@ -0,0 +1,795 @@
+package ux
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/gookit/color"
+	"github.com/peterh/liner"
+
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goutils/logger"
+)
+
+// ANSI escape sequences used to draw the form
+const (
+	tuiClearScreen = "\x1b[H\x1b[2J"
+	tuiHideCursor  = "\x1b[?25l"
+	tuiShowCursor  = "\x1b[?25h"
+	tuiTextCursor  = "\x1b[7m \x1b[0m"
+	tuiLineFeed    = "\r\n"
+)
+
+// keys read from the terminal
+type tuiKey int
+
+const (
+	keyNone tuiKey = iota
+	keyRune
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyTab
+	keyBackTab
+	keyEnter
+	keyBackspace
+	keyEscape
+	keyAbort
+)
+
+// kinds of rows in the form
+type tuiRowKind int
+
+const (
+	fieldRow tuiRowKind = iota
+	choiceRow
+	repeatableRow
+	submitRow
+)
+
+// A row of the form. fields of containers
+// are shown below the container's choice
+// row and refer to the container as their
+// input as that is the input the cursor
+// is positioned at to set their values.
+type tuiRow struct {
+	kind  tuiRowKind
+	input forms.Input
+	field *forms.InputField
+
+	// enabled inputs of a container
+	members []forms.Input
+}
+
+// A full screen form which shows all the enabled
+// inputs of the form at once. the arrow keys move
+// between the inputs, containers are shown as radio
+// groups and fields with accepted values as select
+// lists. values are edited inline and validation
+// errors are shown next to the field. items of
+// repeatable groups are not edited in this form
+// and should be added using a TextForm.
+type TUIForm struct {
+	title,
+	heading string
+
+	inputGroup *forms.InputGroup
+
+	in  io.Reader
+	out io.Writer
+
+	rows  []tuiRow
+	focus int
+
+	// the value being edited in the focused
+	// row and whether it has been changed
+	edit    []rune
+	editing bool
+
+	// the selected input of each container
+	choices map[forms.Input]forms.Input
+	// errors to show next to each input. form
+	// errors not of any input are keyed by ""
+	errors map[string]string
+}
+
+func NewTUIForm(
+	title, heading string,
+	input forms.Input,
+) (*TUIForm, error) {
+
+	var (
+		ok         bool
+		inputGroup *forms.InputGroup
+	)
+
+	if inputGroup, ok = input.(*forms.InputGroup); !ok {
+		return nil, fmt.Errorf("input is not of type forms.InputGroup: %#v", input)
+	}
+
+	return &TUIForm{
+		title:   title,
+		heading: heading,
+
+		inputGroup: inputGroup,
+
+		in:  os.Stdin,
+		out: os.Stdout,
+	}, nil
+}
+
+// in: in  - the terminal keys are read from. if it is
+//           a terminal it will be put into raw mode
+//           while input is being gathered.
+// in: out - the terminal the form is drawn on
+func (tf *TUIForm) SetTerminal(in io.Reader, out io.Writer) {
+	tf.in = in
+	tf.out = out
+}
+
+// gathers input for the form until it is submitted
+//
+// in: width - the width of the form
+// in: tags  - the tags of the inputs to gather
+// out: an InputAbortedError if input was aborted
+func (tf *TUIForm) GetInput(
+	width int,
+	tags ...string,
+) error {
+
+	var (
+		err error
+
+		key  tuiKey
+		r    rune
+		done bool
+
+		restore func()
+	)
+
+	if terminal, ok := tf.in.(*os.File); ok {
+		if restore, err = makeRaw(int(terminal.Fd())); err != nil {
+			logger.DebugMessage(
+				"Unable to put terminal in raw mode: %s", err.Error())
+		} else {
+			defer restore()
+		}
+	}
+
+	fmt.Fprint(tf.out, tuiHideCursor)
+	defer fmt.Fprint(tf.out, tuiShowCursor+tuiLineFeed)
+
+	tf.choices = make(map[forms.Input]forms.Input)
+	tf.errors = make(map[string]string)
+	tf.layout(tags)
+	tf.focusRow(0)
+
+	reader := bufio.NewReader(tf.in)
+	for !done {
+		tf.render(width)
+
+		if key, r, err = readKey(reader); err != nil {
+			if err == io.EOF {
+				return &InputAbortedError{Form: tf.inputGroup.Name(), Err: err}
+			}
+			return err
+		}
+
+		row := tf.rows[tf.focus]
+		switch key {
+		case keyAbort:
+			return &InputAbortedError{Form: tf.inputGroup.Name(), Err: liner.ErrPromptAborted}
+
+		case keyUp, keyBackTab:
+			tf.move(-1, tags)
+
+		case keyDown, keyTab:
+			tf.move(1, tags)
+
+		case keyEnter:
+			if row.kind == submitRow {
+				done = tf.submit(tags)
+			} else {
+				tf.move(1, tags)
+			}
+
+		case keyLeft, keyRight:
+			delta := 1
+			if key == keyLeft {
+				delta = -1
+			}
+			if row.kind == choiceRow {
+				tf.selectInput(row, delta, tags)
+			} else if row.kind == fieldRow && len(row.field.AcceptedValues()) > 0 {
+				tf.selectValue(row, delta, tags)
+			}
+
+		case keyBackspace:
+			if row.kind == fieldRow && len(row.field.AcceptedValues()) == 0 && len(tf.edit) > 0 {
+				tf.edit = tf.edit[:len(tf.edit)-1]
+				tf.editing = true
+			}
+
+		case keyRune:
+			if row.kind == fieldRow && len(row.field.AcceptedValues()) == 0 {
+				tf.edit = append(tf.edit, r)
+				tf.editing = true
+			}
+
+		case keyEscape:
+			// discard changes to the focused value
+			if row.kind == fieldRow {
+				delete(tf.errors, row.field.Name())
+			}
+			tf.focusRow(tf.focus)
+		}
+	}
+	tf.render(width)
+	return nil
+}
+
+// walks the inputs of the form with a cursor in the
+// order the cursor gathers them. the cursor continues
+// with the dependents of the selected input of a
+// container only if that input has been set.
+//
+// in: tags  - the tags of the inputs to walk
+// in: visit - called with the cursor at each input.
+//             it returns false to stop the walk.
+func (tf *TUIForm) walk(
+	tags []string,
+	visit func(cursor *forms.InputCursor, input forms.Input) bool,
+) {
+
+	var (
+		err error
+
+		cursor, next *forms.InputCursor
+		input        forms.Input
+	)
+
+	cursor = forms.NewInputCursor(tf.inputGroup, tags...)
+	for cursor = cursor.NextInput(); cursor != nil; cursor = cursor.NextInput() {
+		if input, err = cursor.GetCurrentInput(); err != nil {
+			return
+		}
+		if !visit(cursor, input) {
+			return
+		}
+		if input.Type() == forms.Container {
+			if field := tf.selected(input, tags); field != nil && field.InputSet() && field.HasValue() {
+				if next, err = cursor.SetDefaultInput(field.Name()); err == nil {
+					cursor = next
+				}
+			}
+		}
+	}
+}
+
+// in: container - a container of the form
+// in: tags      - the tags of the inputs being gathered
+// out: the selected input of the container which is the
+//      input chosen by the user, the input that has been
+//      set or the first enabled input of the container
+func (tf *TUIForm) selected(container forms.Input, tags []string) *forms.InputField {
+	return tf.selectedOf(container, container.EnabledInputs(true, tags...))
+}
+
+// in: container - a container of the form
+// in: members   - the enabled inputs of the container
+// out: the selected input of the container
+func (tf *TUIForm) selectedOf(container forms.Input, members []forms.Input) *forms.InputField {
+
+	if len(members) == 0 {
+		return nil
+	}
+	if choice, ok := tf.choices[container]; ok {
+		for _, m := range members {
+			if m == choice {
+				return m.(*forms.InputField)
+			}
+		}
+	}
+	for _, m := range members {
+		if m.(*forms.InputField).InputSet() {
+			return m.(*forms.InputField)
+		}
+	}
+	return members[0].(*forms.InputField)
+}
+
+// lays out the rows of the enabled inputs of the form
+//
+// in: tags - the tags of the inputs being gathered
+func (tf *TUIForm) layout(tags []string) {
+
+	tf.rows = []tuiRow{}
+	tf.walk(tags, func(cursor *forms.InputCursor, input forms.Input) bool {
+
+		switch input.Type() {
+		case forms.Container:
+			members := input.EnabledInputs(true, tags...)
+			if len(members) > 1 {
+				tf.rows = append(tf.rows, tuiRow{kind: choiceRow, input: input, members: members})
+			}
+			if field := tf.selected(input, tags); field != nil {
+				tf.rows = append(tf.rows, tuiRow{kind: fieldRow, input: input, field: field})
+			}
+
+		case forms.Repeatable:
+			tf.rows = append(tf.rows, tuiRow{kind: repeatableRow, input: input})
+
+		default:
+			if input.Enabled(true, tags...) {
+				tf.rows = append(tf.rows, tuiRow{kind: fieldRow, input: input, field: input.(*forms.InputField)})
+			}
+		}
+		return true
+	})
+	tf.rows = append(tf.rows, tuiRow{kind: submitRow})
+}
+
+// in: index - index of the row to focus. the
+//             index is limited to the rows
+//             of the form.
+func (tf *TUIForm) focusRow(index int) {
+
+	if index < 0 {
+		index = 0
+	} else if index >= len(tf.rows) {
+		index = len(tf.rows) - 1
+	}
+	tf.focus = index
+	tf.editing = false
+	tf.edit = []rune{}
+
+	if row := tf.rows[index]; row.kind == fieldRow {
+		if value := row.field.Value(); value != nil {
+			tf.edit = []rune(*value)
+		}
+	}
+}
+
+// lays out the rows of the form again keeping
+// the focus on the row that was focused
+//
+// in: tags - the tags of the inputs being gathered
+func (tf *TUIForm) relayout(tags []string) {
+
+	focused := tf.rows[tf.focus]
+	tf.layout(tags)
+	for i, row := range tf.rows {
+		if row.kind == focused.kind && row.input == focused.input && row.field == focused.field {
+			tf.focus = i
+			return
+		}
+	}
+	if tf.focus >= len(tf.rows) {
+		tf.focus = len(tf.rows) - 1
+	}
+}
+
+// in: delta - number of rows to move the focus by
+// in: tags  - the tags of the inputs being gathered
+func (tf *TUIForm) move(delta int, tags []string) {
+
+	if !tf.commit(tags) {
+		// focus stays on the field
+		// until its value is valid
+		return
+	}
+	tf.relayout(tags)
+	tf.focusRow(tf.focus + delta)
+}
+
+// sets the value being edited in the focused row
+//
+// in: tags - the tags of the inputs being gathered
+// out: whether the value was valid
+func (tf *TUIForm) commit(tags []string) bool {
+
+	row := tf.rows[tf.focus]
+	if row.kind != fieldRow || !tf.editing {
+		return true
+	}
+	tf.editing = false
+
+	if len(tf.edit) == 0 {
+		// clearing a value resets
+		// the field to its default
+		row.field.ClearInput()
+		delete(tf.errors, row.field.Name())
+		return true
+	}
+	value := string(tf.edit)
+	return tf.setInput(row, &value, tags)
+}
+
+// in: row   - the row of the field to set
+// in: value - the value to set or nil to set the field's current value
+// in: tags  - the tags of the inputs being gathered
+// out: whether the value was set
+func (tf *TUIForm) setInput(row tuiRow, value *string, tags []string) bool {
+
+	var (
+		err error
+	)
+
+	err = fmt.Errorf("input is no longer enabled")
+	tf.walk(tags, func(cursor *forms.InputCursor, input forms.Input) bool {
+		if input != row.input {
+			return true
+		}
+		if value == nil {
+			_, err = cursor.SetDefaultInput(row.field.Name())
+		} else {
+			_, err = cursor.SetInput(row.field.Name(), *value)
+		}
+		return false
+	})
+	if err != nil {
+		tf.errors[row.field.Name()] = err.Error()
+		return false
+	}
+	delete(tf.errors, row.field.Name())
+
+	if row.input.Type() == forms.Container {
+		// only one input of a container may be set
+		for _, m := range row.input.Inputs() {
+			if f := m.(*forms.InputField); f != row.field && f.InputSet() {
+				f.ClearInput()
+			}
+		}
+		tf.choices[row.input] = row.field
+	}
+	return true
+}
+
+// selects another input of a container
+//
+// in: row   - the choice row of the container
+// in: delta - number of inputs to move the selection by
+// in: tags  - the tags of the inputs being gathered
+func (tf *TUIForm) selectInput(row tuiRow, delta int, tags []string) {
+
+	selected := tf.selectedOf(row.input, row.members)
+	for i, m := range row.members {
+		if m == forms.Input(selected) {
+			n := len(row.members)
+			tf.choices[row.input] = row.members[((i+delta)%n+n)%n]
+			break
+		}
+	}
+	tf.relayout(tags)
+}
+
+// sets the field to another of its accepted values
+//
+// in: row   - the row of the field
+// in: delta - number of values to move the selection by
+// in: tags  - the tags of the inputs being gathered
+func (tf *TUIForm) selectValue(row tuiRow, delta int, tags []string) {
+
+	values := row.field.AcceptedValues()
+	n := len(values)
+
+	i := -1
+	if value := row.field.Value(); value != nil {
+		for j, v := range values {
+			if v == *value {
+				i = j
+				break
+			}
+		}
+	}
+	if i == -1 {
+		if delta > 0 {
+			i = 0
+		} else {
+			i = n - 1
+		}
+	} else {
+		i = ((i+delta)%n + n) % n
+	}
+	if tf.setInput(row, &values[i], tags) {
+		tf.relayout(tags)
+		tf.focusRow(tf.focus)
+	}
+}
+
+// submits the form if all required values have been
+// entered and the form's validation rules are met
+//
+// in: tags - the tags of the inputs being gathered
+// out: whether the form was submitted
+func (tf *TUIForm) submit(tags []string) bool {
+
+	var (
+		err error
+
+		validationErr *forms.FormValidationError
+	)
+
+	tf.errors = make(map[string]string)
+	for _, row := range tf.rows {
+		if row.kind != fieldRow {
+			continue
+		}
+		if !row.field.HasValue() {
+			if !row.field.Optional() {
+				tf.errors[row.field.Name()] = "a value is required"
+			}
+		} else if !row.field.InputSet() {
+			// values which were not entered such as
+			// defaults are accepted as the input
+			tf.setInput(row, nil, tags)
+		}
+	}
+	if len(tf.errors) == 0 {
+		if err = tf.inputGroup.Validate(); err != nil {
+			if errors.As(err, &validationErr) {
+				for _, name := range validationErr.Fields() {
+					messages := []string{}
+					for _, violation := range validationErr.Violations[name] {
+						messages = append(messages, violation.Error())
+					}
+					tf.errors[name] = strings.Join(messages, "; ")
+				}
+			} else {
+				tf.errors[""] = err.Error()
+			}
+		}
+	}
+	if len(tf.errors) == 0 {
+		return true
+	}
+
+	// focus the first row with an error
+	tf.relayout(tags)
+	for i, row := range tf.rows {
+		if row.kind == fieldRow {
+			if _, hasError := tf.errors[row.field.Name()]; hasError {
+				tf.focusRow(i)
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// in: width - the width of the form
+func (tf *TUIForm) render(width int) {
+
+	var (
+		out strings.Builder
+	)
+
+	doubleDivider := strings.Repeat("=", width)
+	singleDivider := strings.Repeat("-", width)
+
+	writeLine := func(s string) {
+		out.WriteString(s)
+		out.WriteString(tuiLineFeed)
+	}
+
+	out.WriteString(tuiClearScreen)
+	writeLine(color.OpBold.Render(tf.title))
+	writeLine(color.OpBold.Render(strings.Repeat("=", len(tf.title))))
+	writeLine("")
+	writeLine(tf.inputGroup.Description())
+	if len(tf.heading) > 0 {
+		writeLine("")
+		writeLine(color.OpItalic.Render(tf.heading))
+	}
+	writeLine(doubleDivider)
+
+	nameLen := 0
+	for _, row := range tf.rows {
+		if l := len(tuiRowName(row)); row.kind != submitRow && l > nameLen {
+			nameLen = l
+		}
+	}
+
+	for i, row := range tf.rows {
+		focused := i == tf.focus
+
+		if row.kind == submitRow {
+			writeLine("")
+		}
+		if focused {
+			out.WriteString("> ")
+		} else {
+			out.WriteString("  ")
+		}
+		if row.kind == submitRow {
+			out.WriteString("[ Submit ]")
+			if message, hasError := tf.errors[""]; hasError {
+				out.WriteString("  ")
+				out.WriteString(color.Red.Render(message))
+			}
+			writeLine("")
+			continue
+		}
+
+		name := tuiRowName(row)
+		out.WriteString(name)
+		out.WriteString(strings.Repeat(" ", nameLen-len(name)))
+		out.WriteString(" : ")
+		out.WriteString(tf.rowValue(row, focused))
+
+		if row.kind == fieldRow {
+			if message, hasError := tf.errors[row.field.Name()]; hasError {
+				out.WriteString("  ")
+				out.WriteString(color.Red.Render("! " + message))
+			}
+		}
+		writeLine("")
+	}
+
+	writeLine(singleDivider)
+	if row := tf.rows[tf.focus]; row.kind != submitRow {
+		if row.kind == fieldRow {
+			writeLine(row.field.Description())
+		} else {
+			writeLine(row.input.Description())
+		}
+	} else {
+		writeLine("Press enter to submit the form.")
+	}
+	writeLine(color.OpFuzzy.Render(
+		"up/down: move, left/right: select, enter: next, esc: revert, ctrl-c: abort",
+	))
+
+	fmt.Fprint(tf.out, out.String())
+}
+
+// in: row     - a row of the form
+// in: focused - whether the row is focused
+// out: the value shown in the row
+func (tf *TUIForm) rowValue(row tuiRow, focused bool) string {
+
+	var (
+		out strings.Builder
+	)
+
+	switch row.kind {
+	case choiceRow:
+		selected := forms.Input(tf.selectedOf(row.input, row.members))
+		for i, m := range row.members {
+			if i > 0 {
+				out.WriteString("  ")
+			}
+			if m == selected {
+				out.WriteString("(*) ")
+			} else {
+				out.WriteString("( ) ")
+			}
+			out.WriteString(m.DisplayName())
+		}
+
+	case repeatableRow:
+		out.WriteString(fmt.Sprintf("%d item(s)", len(row.input.(*forms.RepeatableGroup).Items())))
+
+	case fieldRow:
+		value := ""
+		if v := row.field.Value(); v != nil {
+			value = *v
+		}
+		if len(row.field.AcceptedValues()) > 0 {
+			out.WriteString("< ")
+			out.WriteString(value)
+			out.WriteString(" >")
+			break
+		}
+		if focused {
+			value = string(tf.edit)
+		}
+		if row.field.Sensitive() {
+			value = strings.Repeat("*", len([]rune(value)))
+		}
+		out.WriteString(value)
+		if focused {
+			out.WriteString(tuiTextCursor)
+		}
+	}
+	return out.String()
+}
+
+// in: row - a row of the form
+// out: the name shown for the row
+func tuiRowName(row tuiRow) string {
+
+	var (
+		name string
+	)
+
+	switch row.kind {
+	case submitRow:
+		return ""
+	case fieldRow:
+		name = row.field.DisplayName()
+		if len(name) == 0 {
+			name = row.field.Name()
+		}
+	default:
+		name = row.input.DisplayName()
+		if len(name) == 0 {
+			name = row.input.Name()
+		}
+	}
+	return name
+}
+
+// in: reader - reader of the terminal's input
+// out: the key read and the rune of the key if it is
+//      a printable key
+func readKey(reader *bufio.Reader) (tuiKey, rune, error) {
+
+	var (
+		err error
+
+		r rune
+		b byte
+	)
+
+	if r, _, err = reader.ReadRune(); err != nil {
+		return keyNone, 0, err
+	}
+	switch r {
+	case '\x1b':
+		// an escape sequence is sent
+		// together with its escape
+		if reader.Buffered() == 0 {
+			return keyEscape, r, nil
+		}
+		if b, err = reader.ReadByte(); err != nil {
+			return keyNone, 0, err
+		}
+		if b != '[' && b != 'O' {
+			return keyEscape, r, nil
+		}
+		// skip the sequence's parameters
+		// up to its final byte
+		for {
+			if b, err = reader.ReadByte(); err != nil {
+				return keyNone, 0, err
+			}
+			if b >= 0x40 && b <= 0x7e {
+				break
+			}
+		}
+		switch b {
+		case 'A':
+			return keyUp, 0, nil
+		case 'B':
+			return keyDown, 0, nil
+		case 'C':
+			return keyRight, 0, nil
+		case 'D':
+			return keyLeft, 0, nil
+		case 'Z':
+			return keyBackTab, 0, nil
+		}
+		return keyNone, 0, nil
+
+	case '\r', '\n':
+		return keyEnter, r, nil
+	case '\t':
+		return keyTab, r, nil
+	case '\x7f', '\b':
+		return keyBackspace, r, nil
+	case '\x03', '\x04':
+		// ctrl-c and ctrl-d
+		return keyAbort, r, nil
+	}
+	if unicode.IsPrint(r) {
+		return keyRune, r, nil
+	}
+	return keyNone, r, nil
+}
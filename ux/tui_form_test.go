@@ -0,0 +1,191 @@
+//go:build linux
+
+package ux_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goforms/ux"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Full screen form tests", func() {
+
+	var (
+		err error
+
+		master, slave *os.File
+
+		outputLock sync.Mutex
+		output     bytes.Buffer
+
+		inputGroup  *forms.InputGroup
+		inputValues map[string]*string
+	)
+
+	BeforeEach(func() {
+
+		// the form is run on the slave side of a pseudo
+		// terminal and driven from the master side
+		master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+		if err != nil {
+			Skip(fmt.Sprintf("pseudo terminals are not available: %s", err.Error()))
+		}
+		fd := int(master.Fd())
+		Expect(unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0)).To(Succeed())
+		n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+		Expect(err).ToNot(HaveOccurred())
+		slave, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+		Expect(err).ToNot(HaveOccurred())
+
+		output.Reset()
+		go func() {
+			buf := make([]byte, 4096)
+			for {
+				n, err := master.Read(buf)
+				if err != nil {
+					return
+				}
+				outputLock.Lock()
+				output.Write(buf[:n])
+				outputLock.Unlock()
+			}
+		}()
+
+		inputGroup = forms.NewInputCollection().NewGroup("tui-form", "Cluster settings")
+		inputGroup.NewInputContainer("auth", "Authentication", "Credentials to authenticate with", 1)
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "token", DisplayName: "Token", Description: "An API token", GroupID: 1},
+			{Name: "password", DisplayName: "Password", Description: "A password", GroupID: 1, Sensitive: true},
+			{Name: "provider", DisplayName: "Provider", Description: "The cloud provider", AcceptedValues: []string{"aws", "gcp"}},
+			{Name: "region", DisplayName: "Region", Description: "The region", DependsOn: []string{`provider == "aws"`}},
+		} {
+			_, err = inputGroup.NewInputField(attributes)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		region, err := inputGroup.GetInputField("region")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(region.SetInclusionFilter(`^[a-z]+-[a-z]+-[0-9]$`, "invalid region")).To(Succeed())
+
+		inputValues = make(map[string]*string)
+		for _, f := range inputGroup.InputFields() {
+			s := new(string)
+			inputValues[f.Name()] = s
+			Expect(f.SetValueRef(s)).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		if slave != nil {
+			slave.Close()
+		}
+		if master != nil {
+			master.Close()
+		}
+	})
+
+	// out: the last screen drawn by the form
+	screen := func() string {
+		outputLock.Lock()
+		defer outputLock.Unlock()
+
+		s := output.String()
+		if i := strings.LastIndex(s, "\x1b[2J"); i >= 0 {
+			s = s[i:]
+		}
+		return s
+	}
+
+	typeKeys := func(keys ...string) {
+		for _, k := range keys {
+			_, err = master.Write([]byte(k))
+			Expect(err).ToNot(HaveOccurred())
+			// keys are sent one at a time
+			// as a user would type them
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	runForm := func() chan error {
+		tuiForm, err := ux.NewTUIForm("Cluster Configuration", "", inputGroup)
+		Expect(err).ToNot(HaveOccurred())
+		tuiForm.SetTerminal(slave, slave)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- tuiForm.GetInput(80)
+		}()
+		return done
+	}
+
+	It("gathers input by moving between fields", func() {
+
+		done := runForm()
+		Eventually(screen, time.Second).Should(ContainSubstring(
+			"> Authentication : (*) Token  ( ) Password"))
+		Expect(screen()).To(ContainSubstring("  Provider       : <  >"))
+
+		// select the password input of the container
+		// and enter a value which is masked
+		typeKeys("\x1b[C")
+		Eventually(screen, time.Second).Should(ContainSubstring("( ) Token  (*) Password"))
+		typeKeys("\x1b[B", "s", "e", "c", "r", "e", "t")
+		Eventually(screen, time.Second).Should(ContainSubstring("> Password       : ******"))
+
+		// selecting a provider enables the region
+		typeKeys("\x1b[B", "\x1b[C")
+		Eventually(screen, time.Second).Should(ContainSubstring("> Provider       : < aws >"))
+		Expect(screen()).To(ContainSubstring("  Region         : "))
+
+		// invalid values are shown with their error
+		// and the focus stays on the field
+		typeKeys("\x1b[B", "b", "a", "d", "\x1b[B")
+		Eventually(screen, time.Second).Should(ContainSubstring("! invalid region"))
+		Expect(screen()).To(ContainSubstring("> Region         : bad"))
+
+		typeKeys("\x7f", "\x7f", "\x7f")
+		typeKeys(strings.Split("us-east-1", "")...)
+		typeKeys("\r")
+		Eventually(screen, time.Second).Should(ContainSubstring("> [ Submit ]"))
+		Expect(screen()).ToNot(ContainSubstring("invalid region"))
+
+		typeKeys("\r")
+		Eventually(done, time.Second).Should(Receive(BeNil()))
+
+		Expect(*inputValues["password"]).To(Equal("secret"))
+		Expect(*inputValues["provider"]).To(Equal("aws"))
+		Expect(*inputValues["region"]).To(Equal("us-east-1"))
+		Expect(inputGroup.InputValues()).To(HaveLen(3))
+	})
+
+	It("shows required values that are missing when submitting", func() {
+
+		done := runForm()
+		Eventually(screen, time.Second).Should(ContainSubstring("> Authentication"))
+
+		// switching providers disables the region
+		typeKeys("\x1b[B", "\x1b[B", "\x1b[D")
+		Eventually(screen, time.Second).Should(ContainSubstring("> Provider       : < gcp >"))
+		Expect(screen()).ToNot(ContainSubstring("Region"))
+
+		typeKeys("\x1b[B", "\r")
+		Eventually(screen, time.Second).Should(ContainSubstring("> Token          : "))
+		Expect(screen()).To(ContainSubstring("! a value is required"))
+
+		// ctrl-c aborts the form
+		typeKeys("\x03")
+		var abortedErr *ux.InputAbortedError
+		Eventually(done, time.Second).Should(Receive(BeAssignableToTypeOf(abortedErr)))
+	})
+})
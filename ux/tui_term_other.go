@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+
+package ux
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// in: fd - file descriptor of the terminal
+// out: an error as raw mode is not supported on this platform
+func makeRaw(fd int) (func(), error) {
+	return nil, fmt.Errorf("raw terminal mode is not supported on '%s'", runtime.GOOS)
+}
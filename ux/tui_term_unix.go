@@ -0,0 +1,41 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package ux
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// puts the terminal into raw mode so that keys are
+// read as they are pressed without being echoed
+//
+// in: fd - file descriptor of the terminal
+// out: a function which restores the terminal's previous mode
+func makeRaw(fd int) (func(), error) {
+
+	var (
+		err error
+
+		termios *unix.Termios
+	)
+
+	if termios, err = unix.IoctlGetTermios(fd, ioctlGetTermios); err != nil {
+		return nil, err
+	}
+	prevTermios := *termios
+
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Oflag &^= unix.OPOST
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+
+	if err = unix.IoctlSetTermios(fd, ioctlSetTermios, termios); err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = unix.IoctlSetTermios(fd, ioctlSetTermios, &prevTermios)
+	}, nil
+}
@@ -0,0 +1,152 @@
+package web
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// An HTTP handler which serves an HTML form. forms
+// posted to the handler are validated and rendered
+// again with any errors next to the fields. forms
+// posted from pages of other origins or without an
+// Origin or Referer header are rejected.
+type FormHandler struct {
+	title string
+	form  *HTMLForm
+
+	// called when a form without
+	// errors has been posted
+	submitted http.Handler
+
+	// the form's values are shared
+	// by all requests
+	lock sync.Mutex
+}
+
+// in: title     - title of the page the form is rendered in
+// in: form      - the form to serve
+// in: submitted - handler called when a form without errors
+//                 has been posted. if nil the client is
+//                 redirected to the form.
+func NewFormHandler(
+	title string,
+	form *HTMLForm,
+	submitted http.Handler,
+) *FormHandler {
+
+	return &FormHandler{
+		title:     title,
+		form:      form,
+		submitted: submitted,
+	}
+}
+
+func (h *FormHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.render(w, h.form, http.StatusOK, nil)
+
+	case http.MethodPost:
+		if !sameOrigin(r) {
+			http.Error(w, "form not posted from a page of this origin", http.StatusForbidden)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		h.lock.Lock()
+		form, errors := h.form.submit(r.PostForm)
+		h.lock.Unlock()
+
+		if len(errors) > 0 {
+			// the copy of the form the posted values
+			// were applied to is shown with the errors
+			h.render(w, form, http.StatusUnprocessableEntity, errors)
+		} else if h.submitted != nil {
+			h.submitted.ServeHTTP(w, r)
+		} else {
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+		}
+
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// in: w      - writer of the response
+// in: form   - the form to render
+// in: status - status of the response
+// in: errors - errors to show with the form
+func (h *FormHandler) render(w http.ResponseWriter, form *HTMLForm, status int, errors map[string]string) {
+
+	var (
+		html, page bytes.Buffer
+	)
+
+	h.lock.Lock()
+	err := form.Render(&html, errors)
+	h.lock.Unlock()
+
+	if err == nil {
+		err = pageTemplate.Execute(&page, map[string]interface{}{
+			"Title": h.title,
+			"Form":  template.HTML(html.String()),
+		})
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write(page.Bytes())
+}
+
+// in: r - a request posting a form
+// out: whether the form was posted from a page of the
+//      handler's origin. browsers send the origin of the
+//      page a form is posted from so requests without an
+//      origin or referer are rejected as their origin
+//      cannot be verified.
+func sameOrigin(r *http.Request) bool {
+
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 {
+		if origin = r.Header.Get("Referer"); len(origin) == 0 {
+			return false
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+.choice > fieldset.option { display: none; }
+.choice > input[type=radio]:checked + label + fieldset.option { display: block; }
+.field { margin: 0.5em 0; }
+.field > label { display: block; font-weight: bold; }
+.field.required > label::after { content: " *"; }
+.field > small.description { display: block; color: #555; }
+.dependents { margin-left: 1.5em; }
+.error { color: #b00020; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{.Form}}
+</body>
+</html>
+`))
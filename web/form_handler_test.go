@@ -0,0 +1,357 @@
+package web_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mevansam/goforms/forms"
+	"github.com/mevansam/goforms/web"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Form Handler", func() {
+
+	var (
+		err error
+
+		inputGroup  *forms.InputGroup
+		inputValues map[string]*string
+
+		server *httptest.Server
+		client *http.Client
+	)
+
+	BeforeEach(func() {
+
+		defaultPort := "443"
+
+		inputGroup = forms.NewInputCollection().NewGroup("cluster", "Cluster settings")
+		inputGroup.NewInputContainer("auth", "Authentication", "Credentials to authenticate with", 1)
+		for _, attributes := range []forms.FieldAttributes{
+			{Name: "token", DisplayName: "Token", Description: "An API token", GroupID: 1},
+			{Name: "password", DisplayName: "Password", Description: "A password", GroupID: 1, Sensitive: true},
+			{Name: "provider", DisplayName: "Provider", AcceptedValues: []string{"aws", "gcp"}},
+			{Name: "region", DisplayName: "Region", DependsOn: []string{`provider == "aws"`}},
+			{Name: "port", DisplayName: "Port", InputType: forms.Number, DefaultValue: &defaultPort},
+		} {
+			_, err = inputGroup.NewInputField(attributes)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		region, err := inputGroup.GetInputField("region")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(region.SetInclusionFilter(`^[a-z]+-[a-z]+-[0-9]$`, "invalid region")).To(Succeed())
+
+		inputValues = make(map[string]*string)
+		for _, f := range inputGroup.InputFields() {
+			s := new(string)
+			inputValues[f.Name()] = s
+			Expect(f.SetValueRef(s)).To(Succeed())
+		}
+
+		form, err := web.NewHTMLForm(inputGroup)
+		Expect(err).ToNot(HaveOccurred())
+
+		mux := http.NewServeMux()
+		mux.Handle("/cluster", web.NewFormHandler("Cluster Configuration", form, nil))
+		mux.HandleFunc("/done", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, "done")
+		})
+		server = httptest.NewServer(mux)
+
+		// redirects are not followed so
+		// that they can be asserted
+		client = server.Client()
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func() string {
+		resp, err := client.Get(server.URL + "/cluster")
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return string(body)
+	}
+
+	// out: a request posting the values from the given origin
+	newPost := func(values url.Values, origin string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/cluster", strings.NewReader(values.Encode()))
+		Expect(err).ToNot(HaveOccurred())
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if len(origin) > 0 {
+			req.Header.Set("Origin", origin)
+		}
+		return req
+	}
+
+	post := func(values url.Values) (*http.Response, string) {
+		resp, err := client.Do(newPost(values, server.URL))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return resp, string(body)
+	}
+
+	It("renders the form", func() {
+
+		body := get()
+		Expect(body).To(ContainSubstring("<title>Cluster Configuration</title>"))
+		Expect(body).To(ContainSubstring(`<form method="post" class="goforms" id="form-cluster">`))
+
+		// containers are radio switched fieldsets
+		Expect(body).To(ContainSubstring(`<fieldset class="choice" id="choice-auth">
+<legend>Credentials to authenticate with</legend>
+<input type="radio" id="choice-auth-token" name="choice.auth" value="token" checked>
+<label for="choice-auth-token">Token</label>
+<fieldset class="option">
+<div class="field required">
+<label for="input-token">Token</label>
+<input type="text" id="input-token" name="token" value="">
+<small class="description">An API token</small>
+</div>
+</fieldset>
+<input type="radio" id="choice-auth-password" name="choice.auth" value="password">`))
+		Expect(body).To(ContainSubstring(`<input type="password" id="input-password" name="password" value="">`))
+
+		// accepted values are selects and the
+		// dependents of a field are a section
+		Expect(body).To(ContainSubstring(`<select id="input-provider" name="provider">
+<option value=""></option>
+<option value="aws">aws</option>
+<option value="gcp">gcp</option>
+</select>
+<section class="dependents">
+<div class="field required" data-conditions="provider == &#34;aws&#34;">
+<label for="input-region">Region</label>`))
+
+		Expect(body).To(ContainSubstring(`<input type="number" id="input-port" name="port" value="443">`))
+	})
+
+	It("hides conditional sections whose conditions are not met", func() {
+
+		Expect(inputGroup.SetFieldValue("provider", "gcp")).To(Succeed())
+
+		body := get()
+		Expect(body).To(ContainSubstring(`<option value="gcp" selected>gcp</option>`))
+		Expect(body).To(ContainSubstring(`<div class="field required" data-conditions="provider == &#34;aws&#34;" hidden>`))
+	})
+
+	It("re-renders posted forms with their errors", func() {
+
+		resp, body := post(url.Values{
+			"choice.auth": {"password"},
+			"password":    {"secret"},
+			"provider":    {"aws"},
+			"region":      {"bad"},
+			"port":        {"many"},
+		})
+		Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(ContainSubstring(`<input type="radio" id="choice-auth-password" name="choice.auth" value="password" checked>`))
+		Expect(body).To(ContainSubstring(`<option value="aws" selected>aws</option>`))
+		Expect(body).To(ContainSubstring(`<div class="field required invalid" data-conditions="provider == &#34;aws&#34;">
+<label for="input-region">Region</label>
+<input type="text" id="input-region" name="region" value="">
+<p class="error">invalid region</p>`))
+		Expect(body).To(MatchRegexp(`(?s)<div class="field invalid">\n<label for="input-port">Port</label>.*<p class="error">[^<]+</p>`))
+
+		// sensitive values are never rendered
+		Expect(body).ToNot(ContainSubstring("secret"))
+	})
+
+	It("reports required values and containers without a selection", func() {
+
+		resp, body := post(url.Values{"provider": {"gcp"}})
+		Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(ContainSubstring(`<p class="error">one of the inputs of &#39;Authentication&#39; needs to be selected</p>`))
+
+		resp, body = post(url.Values{"choice.auth": {"token"}, "provider": {"aws"}})
+		Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+		Expect(strings.Count(body, `<p class="error">a value is required</p>`)).To(Equal(2))
+	})
+
+	It("sets the form's values when a valid form is posted", func() {
+
+		resp, _ := post(url.Values{
+			"choice.auth": {"password"},
+			"password":    {"secret"},
+			"provider":    {"aws"},
+			"region":      {"us-east-1"},
+			"port":        {""},
+		})
+		Expect(resp.StatusCode).To(Equal(http.StatusSeeOther))
+		Expect(resp.Header.Get("Location")).To(Equal("/cluster"))
+		Expect(inputGroup.InputValues()).To(Equal(map[string]string{
+			"password": "secret",
+			"provider": "aws",
+			"region":   "us-east-1",
+			"port":     "443",
+		}))
+
+		// the sensitive value is kept when it is not posted again
+		// and fields which are no longer enabled are cleared
+		resp, _ = post(url.Values{
+			"choice.auth": {"password"},
+			"provider":    {"gcp"},
+			"region":      {"us-east-1"},
+			"port":        {"8443"},
+		})
+		Expect(resp.StatusCode).To(Equal(http.StatusSeeOther))
+		Expect(inputGroup.InputValues()).To(Equal(map[string]string{
+			"password": "secret",
+			"provider": "gcp",
+			"port":     "8443",
+		}))
+		Expect(*inputValues["region"]).To(BeEmpty())
+	})
+
+	It("does not change the form's values when a posted form has errors", func() {
+
+		resp, _ := post(url.Values{
+			"choice.auth": {"token"},
+			"token":       {"abc"},
+			"provider":    {"aws"},
+			"region":      {"us-east-1"},
+		})
+		Expect(resp.StatusCode).To(Equal(http.StatusSeeOther))
+
+		resp, _ = post(url.Values{
+			"choice.auth": {"token"},
+			"token":       {"def"},
+			"provider":    {"aws"},
+			"region":      {"bad"},
+			"port":        {"8443"},
+		})
+		Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+		Expect(inputGroup.InputValues()).To(Equal(map[string]string{
+			"token":    "abc",
+			"provider": "aws",
+			"region":   "us-east-1",
+			"port":     "443",
+		}))
+	})
+
+	It("rejects forms posted from other origins", func() {
+
+		values := url.Values{
+			"choice.auth": {"token"},
+			"token":       {"abc"},
+			"provider":    {"gcp"},
+		}
+		for _, header := range []string{"Origin", "Referer"} {
+			req := newPost(values, "")
+			req.Header.Set(header, "https://attacker.example/page")
+
+			resp, err := client.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		}
+
+		// the origin of forms posted without
+		// either header cannot be verified
+		resp, err := client.Do(newPost(values, ""))
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(inputGroup.InputValues()).To(BeEmpty())
+
+		req := newPost(values, "")
+		req.Header.Set("Referer", server.URL+"/cluster")
+		resp, err = client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusSeeOther))
+	})
+
+	It("binds posted content of fields whose values are read from files without opening any path", func() {
+
+		tmpDir, err := os.MkdirTemp("", "goforms")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmpDir)
+
+		path := filepath.Join(tmpDir, "secret.txt")
+		Expect(os.WriteFile(path, []byte("server file content"), 0600)).To(Succeed())
+
+		certGroup := forms.NewInputCollection().NewGroup("tls", "TLS settings")
+		_, err = certGroup.NewInputField(forms.FieldAttributes{Name: "cert", DisplayName: "Certificate", ValueFromFile: true})
+		Expect(err).ToNot(HaveOccurred())
+		cert := new(string)
+		certField, err := certGroup.GetInputField("cert")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certField.SetValueRef(cert)).To(Succeed())
+
+		form, err := web.NewHTMLForm(certGroup)
+		Expect(err).ToNot(HaveOccurred())
+		certServer := httptest.NewServer(web.NewFormHandler("TLS", form, nil))
+		defer certServer.Close()
+
+		postCert := func(content string) int {
+			req, err := http.NewRequest(http.MethodPost, certServer.URL, strings.NewReader(url.Values{"cert": {content}}.Encode()))
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("Origin", certServer.URL)
+			resp, err := client.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		// a posted path is the content itself
+		Expect(postCert(path)).To(Equal(http.StatusSeeOther))
+		Expect(*cert).To(Equal(path))
+
+		pem := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+		Expect(postCert(pem)).To(Equal(http.StatusSeeOther))
+		Expect(*cert).To(Equal(pem))
+
+		resp, err := client.Get(certServer.URL)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).ToNot(ContainSubstring("server file content"))
+	})
+
+	It("calls the submitted handler when a valid form is posted", func() {
+
+		form, err := web.NewHTMLForm(inputGroup)
+		Expect(err).ToNot(HaveOccurred())
+		handler := web.NewFormHandler("Cluster Configuration", form,
+			http.RedirectHandler("/done", http.StatusSeeOther))
+
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/cluster", strings.NewReader(url.Values{
+			"choice.auth": {"token"},
+			"token":       {"abc"},
+			"provider":    {"gcp"},
+		}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Origin", "http://example.com")
+		handler.ServeHTTP(recorder, req)
+
+		Expect(recorder.Code).To(Equal(http.StatusSeeOther))
+		Expect(recorder.Header().Get("Location")).To(Equal("/done"))
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/cluster", nil))
+		Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
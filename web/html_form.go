@@ -0,0 +1,561 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/mevansam/goforms/forms"
+)
+
+// Prefix of the names of the radio buttons
+// which select the input of a container
+const ChoicePrefix = "choice."
+
+// An HTML rendering of a form. containers are rendered
+// as fieldsets whose inputs are switched with radio
+// buttons, the dependents of a field as a section
+// whose fields are hidden while their conditions are
+// not met, sensitive fields as password inputs and
+// fields with accepted values as selects. items of
+// repeatable groups are shown but not edited.
+type HTMLForm struct {
+	inputGroup *forms.InputGroup
+	tags       []string
+}
+
+// The view of an input rendered by the form template
+type htmlInput struct {
+	Kind string
+
+	Id, Name,
+	Label, Description,
+	Error string
+
+	// attributes of fields
+	Type     string
+	Value    string
+	Options  []htmlOption
+	Required bool
+
+	// conditions of the field and whether
+	// it is hidden as they are not met
+	Conditions string
+	Hidden     bool
+
+	Dependents []*htmlInput
+
+	// inputs of a container
+	Choices []htmlChoice
+
+	// summaries of the items of a repeatable group
+	Items []string
+}
+
+type htmlOption struct {
+	Value    string
+	Selected bool
+}
+
+type htmlChoice struct {
+	Id, Value, Label string
+	Checked          bool
+
+	Input *htmlInput
+}
+
+// in: input - the form to render
+// in: tags  - the tags of the inputs to render
+func NewHTMLForm(
+	input forms.Input,
+	tags ...string,
+) (*HTMLForm, error) {
+
+	var (
+		ok         bool
+		inputGroup *forms.InputGroup
+	)
+
+	if inputGroup, ok = input.(*forms.InputGroup); !ok {
+		return nil, fmt.Errorf("input is not of type forms.InputGroup: %#v", input)
+	}
+
+	return &HTMLForm{
+		inputGroup: inputGroup,
+		tags:       tags,
+	}, nil
+}
+
+// renders the form as an HTML form element
+//
+// in: writer - writer to which the form will be written
+// in: errors - errors to show next to the fields they are
+//              keyed by. errors keyed by "" are shown at
+//              the top of the form.
+func (hf *HTMLForm) Render(writer io.Writer, errors map[string]string) error {
+
+	return formTemplate.ExecuteTemplate(writer, "form", map[string]interface{}{
+		"Name":        hf.inputGroup.Name(),
+		"Description": hf.inputGroup.Description(),
+		"Error":       errors[""],
+		"Inputs":      hf.htmlInputs(hf.inputGroup.Inputs(), errors),
+	})
+}
+
+// sets the values of the form's fields to the values
+// posted with the form. only the selected input of each
+// container is set and the inputs of containers which
+// are not selected and fields which are disabled by the
+// posted values are cleared. an empty value resets a
+// field to its default value other than for sensitive
+// fields whose value is kept as it is never rendered.
+// the posted values are applied to and validated on a
+// copy of the form so the form's values are only changed
+// if all of them are valid.
+//
+// in: values - the values posted with the form
+// out: errors keyed by the name of the field whose value
+//      is invalid or by "" for errors of the form. if
+//      there are no errors the form's values are valid.
+func (hf *HTMLForm) Submit(values url.Values) map[string]string {
+	_, errs := hf.submit(values)
+	return errs
+}
+
+// in: values - the values posted with the form
+// out: a copy of the form with the posted values applied
+//      which shows the values posted when there are errors
+// out: errors keyed by the name of the field whose value
+//      is invalid or by "" for errors of the form
+func (hf *HTMLForm) submit(values url.Values) (*HTMLForm, map[string]string) {
+
+	var (
+		err error
+
+		staged *forms.InputGroup
+	)
+
+	if staged, err = hf.inputGroup.Clone(nil); err != nil {
+		return hf, map[string]string{"": err.Error()}
+	}
+	form := &HTMLForm{
+		inputGroup: staged,
+		tags:       hf.tags,
+	}
+	errs := form.apply(values)
+	if len(errs) == 0 {
+		if err = hf.commit(staged); err != nil {
+			errs[""] = err.Error()
+		}
+	}
+	return form, errs
+}
+
+// applies the posted values to the form's fields
+//
+// in: values - the values posted with the form
+// out: errors keyed by the name of the field whose value
+//      is invalid or by "" for errors of the form
+func (hf *HTMLForm) apply(values url.Values) map[string]string {
+
+	var (
+		err error
+
+		apply      func(inputs []forms.Input)
+		applyField func(f *forms.InputField)
+
+		validationErr *forms.FormValidationError
+	)
+
+	errs := make(map[string]string)
+
+	applyField = func(f *forms.InputField) {
+
+		if !f.Enabled(true, hf.tags...) {
+			clearInputs([]forms.Input{f})
+			return
+		}
+
+		value := postedValue(f, values)
+		if len(value) > 0 {
+			if valueFromFile, _ := f.ValueFromFile(); valueFromFile {
+				// the content is posted in place of the
+				// file so it is never read from a path
+				err = f.SetContent(&value)
+			} else {
+				err = hf.inputGroup.SetFieldValue(f.Name(), value)
+			}
+			if err != nil {
+				errs[f.Name()] = err.Error()
+			} else {
+				f.SetInput()
+			}
+		} else if !f.Sensitive() || !f.InputSet() || !f.HasValue() {
+			f.ClearInput()
+			if f.HasValue() {
+				f.SetInput()
+			} else {
+				errs[f.Name()] = "a value is required"
+			}
+		}
+		apply(f.Inputs())
+	}
+
+	apply = func(inputs []forms.Input) {
+		for _, i := range inputs {
+			switch i.Type() {
+			case forms.Container:
+				enabled := make(map[forms.Input]bool)
+				for _, m := range i.EnabledInputs(true, hf.tags...) {
+					enabled[m] = true
+				}
+				selected := values.Get(ChoicePrefix + i.Name())
+				for _, m := range i.Inputs() {
+					f := m.(*forms.InputField)
+					if enabled[m] && m.Name() == selected {
+						applyField(f)
+					} else if f.InputSet() {
+						f.ClearInput()
+					}
+				}
+				if len(enabled) > 0 && (len(selected) == 0 || !enabled[hf.inputOf(i, selected)]) {
+					errs[""] = fmt.Sprintf("one of the inputs of '%s' needs to be selected", label(i))
+				}
+
+			case forms.Repeatable:
+				// items of repeatable
+				// groups are not edited
+
+			default:
+				applyField(i.(*forms.InputField))
+			}
+		}
+	}
+	apply(hf.inputGroup.Inputs())
+
+	if len(errs) == 0 {
+		if err = hf.inputGroup.Validate(); err != nil {
+			if errors.As(err, &validationErr) {
+				for _, name := range validationErr.Fields() {
+					messages := []string{}
+					for _, violation := range validationErr.Violations[name] {
+						messages = append(messages, violation.Error())
+					}
+					errs[name] = strings.Join(messages, "; ")
+				}
+			} else {
+				errs[""] = err.Error()
+			}
+		}
+	}
+	return errs
+}
+
+// sets the values of the form's fields to the
+// values of the fields of a copy of the form
+//
+// in: staged - the copy of the form posted values were applied to
+func (hf *HTMLForm) commit(staged *forms.InputGroup) error {
+
+	var (
+		err error
+
+		field *forms.InputField
+	)
+
+	for _, f := range staged.InputFields() {
+		if field, err = hf.inputGroup.GetInputField(f.Name()); err != nil {
+			return err
+		}
+		if !f.InputSet() {
+			if field.InputSet() {
+				field.ClearInput()
+			}
+			continue
+		}
+		source := f.ValueSource()
+		if source.Type == forms.DefaultSource {
+			field.ClearInput()
+		} else if value := f.Value(); value != nil {
+			if current := field.Value(); current == nil || *current != *value {
+				// the staged values are set as they are so
+				// that the content of fields whose values
+				// are read from files is not taken as a path
+				if err = field.SetContent(value); err != nil {
+					return err
+				}
+			}
+		}
+		field.SetInput()
+	}
+	return nil
+}
+
+// in: container - a container of the form
+// in: name      - name of an input of the container
+// out: the input of the container with the given name
+func (hf *HTMLForm) inputOf(container forms.Input, name string) forms.Input {
+
+	for _, i := range container.Inputs() {
+		if i.Name() == name {
+			return i
+		}
+	}
+	return nil
+}
+
+// in: inputs - inputs to render
+// in: errors - errors keyed by field name
+// out: the views of the inputs enabled for the form's tags
+func (hf *HTMLForm) htmlInputs(inputs []forms.Input, errors map[string]string) []*htmlInput {
+
+	views := []*htmlInput{}
+	for _, i := range inputs {
+		switch i.Type() {
+
+		case forms.Container:
+			members := i.EnabledInputs(false, hf.tags...)
+			if len(members) == 0 {
+				continue
+			}
+			// the input which has been set is selected
+			selected := members[0]
+			for _, m := range members {
+				if m.(*forms.InputField).InputSet() {
+					selected = m
+					break
+				}
+			}
+			view := &htmlInput{
+				Kind:  "choice",
+				Id:    "choice-" + i.Name(),
+				Name:  ChoicePrefix + i.Name(),
+				Label: i.Description(),
+			}
+			for _, m := range members {
+				view.Choices = append(view.Choices, htmlChoice{
+					Id:      "choice-" + i.Name() + "-" + m.Name(),
+					Value:   m.Name(),
+					Label:   label(m),
+					Checked: m == selected,
+					Input:   hf.htmlField(m.(*forms.InputField), errors),
+				})
+			}
+			views = append(views, view)
+
+		case forms.Repeatable:
+			view := &htmlInput{
+				Kind:        "repeatable",
+				Id:          "input-" + i.Name(),
+				Name:        i.Name(),
+				Label:       label(i),
+				Description: i.Description(),
+			}
+			for _, item := range i.(*forms.RepeatableGroup).Items() {
+				view.Items = append(view.Items, itemSummary(item))
+			}
+			views = append(views, view)
+
+		default:
+			if i.Enabled(false, hf.tags...) {
+				views = append(views, hf.htmlField(i.(*forms.InputField), errors))
+			}
+		}
+	}
+	return views
+}
+
+// in: f      - field to render
+// in: errors - errors keyed by field name
+// out: the view of the field
+func (hf *HTMLForm) htmlField(f *forms.InputField, errors map[string]string) *htmlInput {
+
+	view := &htmlInput{
+		Kind:        "field",
+		Id:          "input-" + f.Name(),
+		Name:        f.Name(),
+		Label:       label(f),
+		Description: f.Description(),
+		Error:       errors[f.Name()],
+		Required:    !f.Optional(),
+		Conditions:  strings.Join(f.Conditions(), " and "),
+		Hidden:      !f.Enabled(true, hf.tags...),
+		Dependents:  hf.htmlInputs(f.Inputs(), errors),
+	}
+	if value := f.Value(); value != nil && !f.Sensitive() {
+		view.Value = *value
+	}
+
+	valueFromFile, _ := f.ValueFromFile()
+	switch {
+	case len(f.AcceptedValues()) > 0:
+		view.Type = "select"
+		view.Options = []htmlOption{{}}
+		for _, v := range f.AcceptedValues() {
+			view.Options = append(view.Options, htmlOption{
+				Value:    v,
+				Selected: v == view.Value,
+			})
+		}
+	case f.Sensitive():
+		view.Type = "password"
+	case f.ListInput():
+		// list items are entered one per line
+		view.Type = "textarea"
+		view.Value = strings.Join(f.Values(), "\n")
+	case valueFromFile, f.Type() == forms.JsonInput:
+		view.Type = "textarea"
+	case f.Type() == forms.Number:
+		view.Type = "number"
+	case f.Type() == forms.HttpUrl:
+		view.Type = "url"
+	case f.Type() == forms.EmailAddress:
+		view.Type = "email"
+	default:
+		view.Type = "text"
+	}
+	return view
+}
+
+// in: inputs - inputs whose values should be cleared
+func clearInputs(inputs []forms.Input) {
+
+	for _, i := range inputs {
+		if f, ok := i.(*forms.InputField); ok && f.InputSet() {
+			f.ClearInput()
+		}
+		if i.Type() != forms.Repeatable {
+			clearInputs(i.Inputs())
+		}
+	}
+}
+
+// in: f      - a field of the form
+// in: values - the values posted with the form
+// out: the value posted for the field
+func postedValue(f *forms.InputField, values url.Values) string {
+
+	value := strings.ReplaceAll(values.Get(f.Name()), "\r\n", "\n")
+	if !f.ListInput() {
+		return value
+	}
+
+	items := []string{}
+	for _, item := range strings.Split(value, "\n") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(items)
+	return string(data)
+}
+
+// in: input - an input of the form
+// out: the label of the input
+func label(input forms.Input) string {
+
+	if name := input.DisplayName(); len(name) > 0 {
+		return name
+	}
+	return input.Name()
+}
+
+// in: item - input group of an item of a repeatable group
+// out: a single line summary of the values of the item
+func itemSummary(item *forms.InputGroup) string {
+
+	var (
+		out strings.Builder
+	)
+
+	for _, f := range item.InputFields() {
+		if value := f.Value(); f.InputSet() && value != nil {
+			if out.Len() > 0 {
+				out.WriteString(", ")
+			}
+			out.WriteString(label(f))
+			out.WriteString(" = ")
+			if f.Sensitive() {
+				out.WriteString("****")
+			} else {
+				out.WriteString(*value)
+			}
+		}
+	}
+	return out.String()
+}
+
+var formTemplate = template.Must(template.New("form").Parse(`
+{{- define "form" -}}
+<form method="post" class="goforms" id="form-{{.Name}}">
+{{- if .Description}}
+<p class="description">{{.Description}}</p>
+{{- end}}
+{{- if .Error}}
+<p class="error">{{.Error}}</p>
+{{- end}}
+{{- range .Inputs}}{{template "input" .}}{{end}}
+<button type="submit">Submit</button>
+</form>
+{{end}}
+
+{{- define "input"}}
+{{- if eq .Kind "choice"}}
+<fieldset class="choice" id="{{.Id}}">
+<legend>{{.Label}}</legend>
+{{- range .Choices}}
+<input type="radio" id="{{.Id}}" name="{{$.Name}}" value="{{.Value}}"{{if .Checked}} checked{{end}}>
+<label for="{{.Id}}">{{.Label}}</label>
+<fieldset class="option">
+{{- template "input" .Input}}
+</fieldset>
+{{- end}}
+</fieldset>
+{{- else if eq .Kind "repeatable"}}
+<fieldset class="repeatable" id="{{.Id}}">
+<legend>{{.Label}}</legend>
+{{- if .Description}}
+<p class="description">{{.Description}}</p>
+{{- end}}
+<ol>
+{{- range .Items}}
+<li>{{.}}</li>
+{{- end}}
+</ol>
+</fieldset>
+{{- else}}
+<div class="field{{if .Required}} required{{end}}{{if .Error}} invalid{{end}}"{{if .Conditions}} data-conditions="{{.Conditions}}"{{end}}{{if .Hidden}} hidden{{end}}>
+<label for="{{.Id}}">{{.Label}}</label>
+{{- if eq .Type "select"}}
+<select id="{{.Id}}" name="{{.Name}}">
+{{- range .Options}}
+<option value="{{.Value}}"{{if .Selected}} selected{{end}}>{{.Value}}</option>
+{{- end}}
+</select>
+{{- else if eq .Type "textarea"}}
+<textarea id="{{.Id}}" name="{{.Name}}">{{.Value}}</textarea>
+{{- else}}
+<input type="{{.Type}}" id="{{.Id}}" name="{{.Name}}" value="{{.Value}}">
+{{- end}}
+{{- if .Description}}
+<small class="description">{{.Description}}</small>
+{{- end}}
+{{- if .Error}}
+<p class="error">{{.Error}}</p>
+{{- end}}
+{{- if .Dependents}}
+<section class="dependents">
+{{- range .Dependents}}{{template "input" .}}{{end}}
+</section>
+{{- end}}
+</div>
+{{- end}}
+{{- end}}
+`))